@@ -0,0 +1,236 @@
+package jio
+
+import "strings"
+
+var _ Schema = new(AlternativesSchema)
+
+// Alternatives Generates a schema object that matches the value with several schemas,
+// such as a field that is either a string or a number of seconds.
+func Alternatives() *AlternativesSchema {
+	return &AlternativesSchema{}
+}
+
+// AlternativesSchema match the value with the schemas added by Try, OneOf and AllOf.
+type AlternativesSchema struct {
+	baseSchema
+}
+
+func (a *AlternativesSchema) clone() *AlternativesSchema {
+	return &AlternativesSchema{baseSchema: a.baseSchema.clone()}
+}
+
+// SetPriority same as AnySchema.SetPriority
+func (a *AlternativesSchema) SetPriority(priority int) *AlternativesSchema {
+	schema := a.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (a *AlternativesSchema) Messages(messages Messages) *AlternativesSchema {
+	schema := a.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (a *AlternativesSchema) Meta(key string, value interface{}) *AlternativesSchema {
+	schema := a.clone()
+	schema.setMeta(key, value)
+	return schema
+}
+
+// PrependTransform same as AnySchema.PrependTransform
+func (a *AlternativesSchema) PrependTransform(f func(*Context)) *AlternativesSchema {
+	schema := a.clone()
+	schema.prependRule(f)
+	return schema
+}
+
+// Transform same as AnySchema.Transform
+func (a *AlternativesSchema) Transform(f func(*Context)) *AlternativesSchema {
+	schema := a.clone()
+	schema.appendRule(f)
+	return schema
+}
+
+// Required same as AnySchema.Required
+func (a *AlternativesSchema) Required() *AlternativesSchema {
+	schema := a.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.abortRule("any.required", nil)
+		}
+	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
+}
+
+// Optional same as AnySchema.Optional
+func (a *AlternativesSchema) Optional() *AlternativesSchema {
+	schema := a.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Skip()
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
+}
+
+// Default same as AnySchema.Default
+func (a *AlternativesSchema) Default(value interface{}) *AlternativesSchema {
+	schema := a.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Value = cloneValue(value)
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
+}
+
+// When same as AnySchema.When
+func (a *AlternativesSchema) When(refPath string, condition interface{}, then Schema) *AlternativesSchema {
+	schema := a.Transform(func(ctx *Context) { a.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
+}
+
+// Try check if the value can pass the validation of any schema, the schemas are tried in order
+// and the first matched schema wins, the value is replaced by the value transformed by it.
+// When the value matches none of the schemas, the errors of the closest schema are thrown,
+// which is the schema with the fewest errors, and the schemas failing on the type of the value are the farthest.
+func (a *AlternativesSchema) Try(schemas ...Schema) *AlternativesSchema {
+	return a.alternatives("try", schemas, func(ctx *Context) {
+		value := ctx.Value
+		branches := make([]ValidationErrors, 0, len(schemas))
+		for _, schema := range schemas {
+			errs, ok := validateBranch(ctx, value, schema)
+			if ok {
+				return
+			}
+			branches = append(branches, errs)
+		}
+		ctx.Value = value
+		if len(branches) > 0 {
+			ctx.Abort(closestErrors(branches, ctx.FieldPath()))
+		}
+	})
+}
+
+// OneOf same as Try, but throws an error of the `alternatives.one` rule when the value matches more than one schema.
+func (a *AlternativesSchema) OneOf(schemas ...Schema) *AlternativesSchema {
+	return a.alternatives("oneOf", schemas, func(ctx *Context) {
+		value := ctx.Value
+		var result interface{}
+		var branches []ValidationErrors
+		matched := 0
+		for _, schema := range schemas {
+			errs, ok := validateBranch(ctx, value, schema)
+			if ok {
+				if matched == 0 {
+					result = ctx.Value
+				}
+				matched++
+				continue
+			}
+			branches = append(branches, errs)
+		}
+		ctx.Value = value
+		switch {
+		case matched == 1:
+			ctx.Value = result
+		case matched > 1:
+			ctx.abortRule("alternatives.one", nil)
+		case len(branches) > 0:
+			ctx.Abort(closestErrors(branches, ctx.FieldPath()))
+		}
+	})
+}
+
+// AllOf check if the value can pass the validation of all schemas, the schemas are applied in order
+// and each schema receives the value transformed by the previous one.
+// The errors of all failed schemas are thrown when AbortEarly is disabled.
+func (a *AlternativesSchema) AllOf(schemas ...Schema) *AlternativesSchema {
+	return a.alternatives("allOf", schemas, func(ctx *Context) {
+		errorsLen := len(ctx.errors)
+		for _, schema := range schemas {
+			ctx.skip = false
+			schema.Validate(ctx)
+			if len(ctx.errors) > errorsLen && ctx.options.abortEarly {
+				break
+			}
+		}
+		ctx.skip = len(ctx.errors) > errorsLen
+	})
+}
+
+func (a *AlternativesSchema) alternatives(name string, schemas []Schema, f func(*Context)) *AlternativesSchema {
+	schema := a.Transform(f)
+	for _, item := range schemas {
+		schema.addRefs(schemaRefs(item)...)
+	}
+	schema.describeLast(name, map[string]interface{}{"schemas": schemas})
+	return schema
+}
+
+// validateBranch validate a copy of the value with the schema, the errors are returned instead of thrown.
+// The copy keeps the defaults and the transforms of a rejected branch out of the value.
+func validateBranch(ctx *Context, value interface{}, schema Schema) (ValidationErrors, bool) {
+	errorsLen := len(ctx.errors)
+	ctx.Value = cloneValue(value)
+	ctx.skip = false
+	schema.Validate(ctx)
+	ctx.skip = false
+	if len(ctx.errors) == errorsLen {
+		return nil, true
+	}
+	errs := append(ValidationErrors(nil), ctx.errors[errorsLen:]...)
+	ctx.resetErrors(errorsLen)
+	return errs, false
+}
+
+// closestErrors returns the errors of the branch closest to match the value at the path.
+// The branches failing on the type of the value are the farthest, then the branch with fewer errors is closer.
+// The first branch wins a tie.
+func closestErrors(branches []ValidationErrors, path string) ValidationErrors {
+	best, bestScore := 0, -1
+	for i, errs := range branches {
+		score := len(errs)
+		for _, err := range errs {
+			if err.Path == path && strings.HasSuffix(err.Rule, ".base") {
+				score += 1 << 20
+			}
+		}
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return branches[best]
+}
+
+// Describe same as AnySchema.Describe
+func (a *AlternativesSchema) Describe() *Description {
+	return a.describe("alternatives")
+}
+
+// Validate a value using the schema
+func (a *AlternativesSchema) Validate(ctx *Context) {
+	if a.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if a.messages != nil {
+		ctx.pushMessages(a.messages)
+		defer ctx.popMessages()
+	}
+	for _, rule := range a.rules {
+		rule(ctx)
+		if ctx.skip {
+			return
+		}
+	}
+}
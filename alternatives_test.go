@@ -0,0 +1,127 @@
+package jio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlternativesSchema_Try(t *testing.T) {
+	schema := Alternatives().Required().Try(
+		String().Trim().Min(1),
+		Number().ParseString().Integer().Transform(func(ctx *Context) { ctx.Value = ctx.Value.(float64) * 1000 }),
+	)
+
+	ctx := NewContext(" 1m ")
+	schema.Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "1m" {
+		t.Error("should keep the value transformed by the first matched schema")
+	}
+	ctx = NewContext(30.0)
+	schema.Validate(ctx)
+	if ctx.Err != nil || ctx.Value != 30000.0 {
+		t.Error("should try the next schema")
+	}
+
+	ctx = NewContext(1.5)
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "number.integer" {
+		t.Error("should report the errors of the closest schema")
+	}
+	ctx = NewContext(true)
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "string.base" {
+		t.Error("should report the errors of the first schema when all schemas fail on the type")
+	}
+	ctx = NewContext(nil)
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" {
+		t.Error("should check the required")
+	}
+
+	ctx = NewContext(nil)
+	Alternatives().Try(String()).Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should be optional by default")
+	}
+}
+
+func TestAlternativesSchema_Try_Closest(t *testing.T) {
+	schema := Object().Keys(K{
+		"contact": Alternatives().Try(
+			Object().Keys(K{"email": String().Required().Regex("@")}),
+			Object().Keys(K{"phone": String().Required().Min(5), "area": String().Required()}),
+		),
+	})
+	ctx := NewContext(map[string]interface{}{"contact": map[string]interface{}{"phone": "123"}}, AbortEarly(false))
+	schema.Validate(ctx)
+	errs, ok := ctx.Err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Path != "contact.email" {
+		t.Error("should report the branch with the fewest errors", ctx.Err)
+	}
+}
+
+func TestAlternativesSchema_Try_Rejected(t *testing.T) {
+	rejected := Object().Keys(K{"a": String().Default("leaked"), "b": Number().Required()})
+	matched := Object().Keys(K{"c": String()})
+	for name, schema := range map[string]*AlternativesSchema{
+		"try":   Alternatives().Try(rejected, matched),
+		"oneOf": Alternatives().OneOf(rejected, matched),
+	} {
+		value := map[string]interface{}{"c": "x"}
+		ctx := NewContext(value)
+		schema.Validate(ctx)
+		expected := map[string]interface{}{"c": "x"}
+		if ctx.Err != nil || !reflect.DeepEqual(ctx.Value, expected) || !reflect.DeepEqual(value, expected) {
+			t.Error(name, "should keep the defaults of the rejected schema out of the value", ctx.Value, value)
+		}
+	}
+}
+
+func TestAlternativesSchema_OneOf(t *testing.T) {
+	schema := Alternatives().OneOf(
+		Object().Keys(K{"email": String().Required()}),
+		Object().Keys(K{"phone": String().Required()}),
+	)
+	ctx := NewContext(map[string]interface{}{"email": "a"})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should match exactly one schema")
+	}
+	ctx = NewContext(map[string]interface{}{"email": "a", "phone": "b"})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "alternatives.one" {
+		t.Error("should reject the value matching more than one schema")
+	}
+	ctx = NewContext(map[string]interface{}{})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" || err.Path != "email" {
+		t.Error("should report the errors of the closest schema")
+	}
+}
+
+func TestAlternativesSchema_AllOf(t *testing.T) {
+	schema := Alternatives().AllOf(String().Trim(), String().Min(3), String().Regex("^[a-z]+$"))
+	ctx := NewContext(" jio ")
+	schema.Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "jio" {
+		t.Error("should apply the schemas in order")
+	}
+
+	ctx = NewContext(" J ", AbortEarly(false))
+	schema.Validate(ctx)
+	if errs, ok := ctx.Err.(ValidationErrors); !ok || len(errs) != 2 {
+		t.Error("should report the errors of all schemas")
+	}
+}
+
+func TestAlternativesSchema_Describe(t *testing.T) {
+	description := Alternatives().Try(String(), Number()).Describe()
+	if description.Type != "alternatives" || description.Rules[0].Name != "try" ||
+		len(description.Rules[0].Args["schemas"].([]*Description)) != 2 {
+		t.Error("should describe the schemas")
+	}
+	result := JSONSchema(Alternatives().Try(String().Required(), Number().Required()))
+	if len(result["anyOf"].([]interface{})) != 2 {
+		t.Error("should export Try as anyOf")
+	}
+}
@@ -1,93 +1,117 @@
 package jio
 
-import (
-	"fmt"
-)
-
 var _ Schema = new(AnySchema)
 
 // Any Generates a schema object that matches any data type
 func Any() *AnySchema {
-	return &AnySchema{
-		rules: make([]func(*Context), 0, 3),
-	}
+	return &AnySchema{}
 }
 
 // AnySchema match any data type
 type AnySchema struct {
 	baseSchema
+}
 
-	required *bool
-	rules    []func(*Context)
+func (a *AnySchema) clone() *AnySchema {
+	return &AnySchema{baseSchema: a.baseSchema.clone()}
 }
 
 // SetPriority set priority to the schema.
 // A schema with a higher priority under the same object will be validate first.
 func (a *AnySchema) SetPriority(priority int) *AnySchema {
-	a.priority = priority
-	return a
+	schema := a.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages override the messages of the rules for this schema and its children, just like joi's messages().
+// The key is the rule code, such as `string.min`, see Messages for the placeholders.
+func (a *AnySchema) Messages(messages Messages) *AnySchema {
+	schema := a.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta attach the metadata to the schema, such as title and description, which is returned by Describe
+// and exported by JSONSchema. It doesn't change the validation.
+func (a *AnySchema) Meta(key string, value interface{}) *AnySchema {
+	schema := a.clone()
+	schema.setMeta(key, value)
+	return schema
 }
 
 // PrependTransform run your transform function before othor rules.
 func (a *AnySchema) PrependTransform(f func(*Context)) *AnySchema {
-	a.rules = append([]func(*Context){f}, a.rules...)
-	return a
+	schema := a.clone()
+	schema.prependRule(f)
+	return schema
 }
 
 // Transform append your transform function to rules.
 func (a *AnySchema) Transform(f func(*Context)) *AnySchema {
-	a.rules = append(a.rules, f)
-	return a
+	schema := a.clone()
+	schema.appendRule(f)
+	return schema
 }
 
 // Required mark a key as required which will not allow undefined or null as value.
 // All keys are optional by default.
 func (a *AnySchema) Required() *AnySchema {
-	a.required = boolPtr(true)
-	return a.PrependTransform(func(ctx *Context) {
+	schema := a.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Abort(fmt.Errorf("field `%s` is required", ctx.FieldPath()))
+			ctx.abortRule("any.required", nil)
 		}
 	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
 }
 
 // Optional mark a key as optional which will allow undefined or null as values.
 // When the value of the key is undefined or null, the following check rule will be skip.
 // Used to annotate the schema for readability as all keys are optional by default.
 func (a *AnySchema) Optional() *AnySchema {
-	a.required = boolPtr(false)
-	return a.PrependTransform(func(ctx *Context) {
+	schema := a.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Skip()
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
 }
 
 // Default set a default value if the original value is undefined or null.
 func (a *AnySchema) Default(value interface{}) *AnySchema {
-	a.required = boolPtr(false)
-	return a.PrependTransform(func(ctx *Context) {
+	schema := a.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Value = value
+			ctx.Value = cloneValue(value)
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Set just set a value for the key and don't care the origin value.
 func (a *AnySchema) Set(value interface{}) *AnySchema {
-	return a.Transform(func(ctx *Context) {
+	schema := a.Transform(func(ctx *Context) {
 		ctx.Value = value
 	})
+	schema.describeLast("set", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Equal check the provided value is equal to the value of the key.
 func (a *AnySchema) Equal(value interface{}) *AnySchema {
-	return a.Transform(func(ctx *Context) {
+	schema := a.Transform(func(ctx *Context) {
 		if value != ctx.Value {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not %v", ctx.FieldPath(), ctx.Value, value))
+			ctx.failRule("any.equal", map[string]interface{}{"expected": value})
 			return
 		}
 	})
+	schema.describeLast("equal", map[string]interface{}{"expected": value})
+	return schema
 }
 
 // When add a conditional schema based on another key value
@@ -97,13 +121,18 @@ func (a *AnySchema) Equal(value interface{}) *AnySchema {
 // If condition is value, then check the condition is equal to the reference value.
 // When the condition is true, the then schema will be applied to the current key value.
 // Otherwise, nothing will be done.
+// Under the same object, the key is validated after the keys referenced by When.
 func (a *AnySchema) When(refPath string, condition interface{}, then Schema) *AnySchema {
-	return a.Transform(func(ctx *Context) { a.when(ctx, refPath, condition, then) })
+	schema := a.Transform(func(ctx *Context) { a.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
 }
 
 // Valid add the provided values into the allowed whitelist and mark them as the only valid values allowed.
 func (a *AnySchema) Valid(values ...interface{}) *AnySchema {
-	return a.Transform(func(ctx *Context) {
+	schema := a.Transform(func(ctx *Context) {
 		var isValid bool
 		for _, v := range values {
 			if v == ctx.Value {
@@ -112,16 +141,28 @@ func (a *AnySchema) Valid(values ...interface{}) *AnySchema {
 			}
 		}
 		if !isValid {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not in %v", ctx.FieldPath(), ctx.Value, values))
+			ctx.failRule("any.valid", map[string]interface{}{"valids": values})
 			return
 		}
 	})
+	schema.describeLast("valid", map[string]interface{}{"valids": values})
+	return schema
+}
+
+// Describe returns the serializable description of the schema, see Description.
+func (a *AnySchema) Describe() *Description {
+	return a.describe("any")
 }
 
 // Validate a value using the schema
 func (a *AnySchema) Validate(ctx *Context) {
-	if a.required == nil {
-		a.Optional()
+	if a.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if a.messages != nil {
+		ctx.pushMessages(a.messages)
+		defer ctx.popMessages()
 	}
 	for _, rule := range a.rules {
 		rule(ctx)
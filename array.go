@@ -1,152 +1,260 @@
 package jio
 
 import (
-	"errors"
-	"fmt"
 	"reflect"
+	"strconv"
 )
 
 var _ Schema = new(ArraySchema)
 
 // Array Generates a schema object that matches array data type
 func Array() *ArraySchema {
-	return &ArraySchema{
-		rules: make([]func(*Context), 0, 3),
-	}
+	return &ArraySchema{}
 }
 
 // ArraySchema match array data type
 type ArraySchema struct {
 	baseSchema
+}
 
-	required *bool
-	rules    []func(*Context)
+func (a *ArraySchema) clone() *ArraySchema {
+	return &ArraySchema{baseSchema: a.baseSchema.clone()}
 }
 
 // SetPriority same as AnySchema.SetPriority
 func (a *ArraySchema) SetPriority(priority int) *ArraySchema {
-	a.priority = priority
-	return a
+	schema := a.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (a *ArraySchema) Messages(messages Messages) *ArraySchema {
+	schema := a.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (a *ArraySchema) Meta(key string, value interface{}) *ArraySchema {
+	schema := a.clone()
+	schema.setMeta(key, value)
+	return schema
 }
 
 // PrependTransform same as AnySchema.PrependTransform
 func (a *ArraySchema) PrependTransform(f func(*Context)) *ArraySchema {
-	a.rules = append([]func(*Context){f}, a.rules...)
-	return a
+	schema := a.clone()
+	schema.prependRule(f)
+	return schema
 }
 
 // Transform same as AnySchema.Transform
 func (a *ArraySchema) Transform(f func(*Context)) *ArraySchema {
-	a.rules = append(a.rules, f)
-	return a
+	schema := a.clone()
+	schema.appendRule(f)
+	return schema
 }
 
 // Required same as AnySchema.Required
 func (a *ArraySchema) Required() *ArraySchema {
-	a.required = boolPtr(true)
-	return a.PrependTransform(func(ctx *Context) {
+	schema := a.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Abort(fmt.Errorf("field `%s` is required", ctx.FieldPath()))
+			ctx.abortRule("any.required", nil)
 		}
 	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
 }
 
 // Optional same as AnySchema.Optional
 func (a *ArraySchema) Optional() *ArraySchema {
-	a.required = boolPtr(false)
-	return a.PrependTransform(func(ctx *Context) {
+	schema := a.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Skip()
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
 }
 
 // Default same as AnySchema.Default
 func (a *ArraySchema) Default(value interface{}) *ArraySchema {
-	a.required = boolPtr(false)
-	return a.PrependTransform(func(ctx *Context) {
+	schema := a.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Value = value
+			ctx.Value = cloneValue(value)
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
 }
 
 // When same as AnySchema.When
 func (a *ArraySchema) When(refPath string, condition interface{}, then Schema) *ArraySchema {
-	return a.Transform(func(ctx *Context) { a.when(ctx, refPath, condition, then) })
+	schema := a.Transform(func(ctx *Context) { a.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
 }
 
 // Check use the provided function to validate the value of the key.
 // Throws an error when the value is not a slice.
 func (a *ArraySchema) Check(f func(interface{}) error) *ArraySchema {
-	return a.Transform(func(ctx *Context) {
+	schema := a.Transform(func(ctx *Context) {
 		if !ctx.AssertKind(reflect.Slice) {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not array", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("array.base", nil)
 			return
 		}
 		if err := f(ctx.Value); err != nil {
-			ctx.Abort(fmt.Errorf("field `%s` value %v %s", ctx.FieldPath(), ctx.Value, err.Error()))
+			ctx.failCheck("array.check", err)
+		}
+	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// check is the built-in version of Check, throws an error of the rule when f returns false.
+func (a *ArraySchema) check(rule string, args map[string]interface{}, f func(reflect.Value) bool) *ArraySchema {
+	schema := a.Transform(func(ctx *Context) {
+		if !ctx.AssertKind(reflect.Slice) {
+			ctx.abortRule("array.base", nil)
+			return
+		}
+		if !f(reflect.ValueOf(ctx.Value)) {
+			ctx.failRule(rule, args)
 		}
 	})
+	schema.describeLast(rule, args)
+	return schema
 }
 
-// Items check if this value can pass the validation of any schema.
+// Items check if each item of this value can pass the validation of any schema.
+// The items are validated with the field path of their index, such as `size.1`.
+// When an item matches none of the schemas, the errors of the first schema will be thrown.
+// Otherwise the item is replaced by the value transformed by the matched schema,
+// unless the transformed value can't be assigned to the slice's element type.
 func (a *ArraySchema) Items(schemas ...Schema) *ArraySchema {
-	return a.Check(func(ctxValue interface{}) error {
+	schema := a.Transform(func(ctx *Context) {
+		if !ctx.AssertKind(reflect.Slice) {
+			ctx.abortRule("array.base", nil)
+			return
+		}
+		ctxValue := ctx.Value
+		fields := ctx.fields
+
+		defer func() {
+			ctx.fields = fields
+			ctx.Value = ctxValue
+		}()
+
 		ctxRV := reflect.ValueOf(ctxValue)
 		for i := 0; i < ctxRV.Len(); i++ {
-			rv := ctxRV.Index(i).Interface()
-			var isValid bool
-			for _, schema := range schemas {
-				ctxNew := NewContext(rv)
-				schema.Validate(ctxNew)
-				if ctxNew.Err == nil {
-					isValid = true
-					break
+			ctx.fields = append(fields, strconv.Itoa(i))
+			itemRV := ctxRV.Index(i)
+			if !validateItem(ctx, itemRV.Interface(), schemas) {
+				if ctx.options.abortEarly {
+					return
 				}
+				continue
 			}
-			if !isValid {
-				return errors.New("not valid type")
+			if items, ok := ctxValue.([]interface{}); ok {
+				items[i] = ctx.Value
+			} else {
+				setItem(itemRV, ctx.Value)
 			}
 		}
-		return nil
+		ctx.skip = false
 	})
+	for _, item := range schemas {
+		schema.addRefs(schemaRefs(item)...)
+	}
+	schema.describeLast("items", map[string]interface{}{"schemas": schemas})
+	return schema
+}
+
+// setItem assign the value to the item if the type of the value is assignable.
+func setItem(itemRV reflect.Value, value interface{}) {
+	if value == nil {
+		switch itemRV.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice, reflect.Ptr:
+			itemRV.Set(reflect.Zero(itemRV.Type()))
+		}
+		return
+	}
+	valueRV := reflect.ValueOf(value)
+	if valueRV.Type().AssignableTo(itemRV.Type()) {
+		itemRV.Set(valueRV)
+	}
+}
+
+// validateItem validate the value with the schemas in order until one of them passes.
+// If all schemas fail, only the errors of the first schema are kept.
+// Every schema but the last validates a clone of the value, so a failed schema can't change the nested values
+// seen by the next one, and only the value of the passed schema is kept.
+func validateItem(ctx *Context, value interface{}, schemas []Schema) bool {
+	errorsLen := len(ctx.errors)
+	var firstErrors ValidationErrors
+	for i, schema := range schemas {
+		ctx.Value = value
+		if i < len(schemas)-1 {
+			ctx.Value = cloneValue(value)
+		}
+		ctx.skip = false
+		schema.Validate(ctx)
+		if len(ctx.errors) == errorsLen {
+			return true
+		}
+		if i == 0 {
+			firstErrors = append(firstErrors, ctx.errors[errorsLen:]...)
+		}
+		ctx.resetErrors(errorsLen)
+	}
+	if len(firstErrors) == 0 {
+		return true
+	}
+	ctx.Abort(firstErrors)
+	return false
 }
 
 // Min check if the length of this slice is greater than or equal to the provided length.
 func (a *ArraySchema) Min(min int) *ArraySchema {
-	return a.Check(func(ctxValue interface{}) error {
-		if reflect.ValueOf(ctxValue).Len() < min {
-			return fmt.Errorf("length less than %d", min)
-		}
-		return nil
+	return a.check("array.min", map[string]interface{}{"limit": min}, func(ctxRV reflect.Value) bool {
+		return ctxRV.Len() >= min
 	})
 }
 
 // Max check if the length of this slice is less than or equal to the provided length.
 func (a *ArraySchema) Max(max int) *ArraySchema {
-	return a.Check(func(ctxValue interface{}) error {
-		if reflect.ValueOf(ctxValue).Len() > max {
-			return fmt.Errorf("length exceeded %d", max)
-		}
-		return nil
+	return a.check("array.max", map[string]interface{}{"limit": max}, func(ctxRV reflect.Value) bool {
+		return ctxRV.Len() <= max
 	})
 }
 
 // Length check if the length of this slice is equal to the provided length.
 func (a *ArraySchema) Length(length int) *ArraySchema {
-	return a.Check(func(ctxValue interface{}) error {
-		if reflect.ValueOf(ctxValue).Len() != length {
-			return fmt.Errorf("length not equal to %d", length)
-		}
-		return nil
+	return a.check("array.length", map[string]interface{}{"limit": length}, func(ctxRV reflect.Value) bool {
+		return ctxRV.Len() == length
 	})
 }
 
+// Describe same as AnySchema.Describe
+func (a *ArraySchema) Describe() *Description {
+	return a.describe("array")
+}
+
 // Validate same as AnySchema.Validate
 func (a *ArraySchema) Validate(ctx *Context) {
-	if a.required == nil {
-		a.Optional()
+	if a.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if a.messages != nil {
+		ctx.pushMessages(a.messages)
+		defer ctx.popMessages()
 	}
 	for _, rule := range a.rules {
 		rule(ctx)
@@ -154,9 +262,7 @@ func (a *ArraySchema) Validate(ctx *Context) {
 			return
 		}
 	}
-	if ctx.Err == nil {
-		if !ctx.AssertKind(reflect.Slice) {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not array", ctx.FieldPath(), ctx.Value))
-		}
+	if !ctx.AssertKind(reflect.Slice) {
+		ctx.abortRule("array.base", nil)
 	}
 }
@@ -197,3 +197,79 @@ func TestArraySchema_Validate(t *testing.T) {
 		t.Error("not array")
 	}
 }
+
+func TestArraySchema_ItemsContext(t *testing.T) {
+	schema := Object().Keys(K{
+		"max": Number(),
+		"window": Object().Keys(K{
+			"size": Array().Items(Number().Transform(func(ctx *Context) {
+				max, _ := ctx.Ref("max")
+				if ctx.Value.(float64) > max.(float64) {
+					ctx.Abort(errors.New("too large"))
+				}
+			})),
+		}),
+	})
+	ctx := NewContext(map[string]interface{}{
+		"max":    float64(100),
+		"window": map[string]interface{}{"size": []interface{}{float64(1), float64(500)}},
+	})
+	schema.Validate(ctx)
+	validationErr, ok := ctx.Err.(*ValidationError)
+	if !ok || validationErr.Path != "window.size.1" || validationErr.Error() != "too large" {
+		t.Error("should throw the item error with the index")
+	}
+
+	schema = Object().Keys(K{
+		"list": Array().Items(Number().Integer(), String()),
+	})
+	ctx = NewContext(map[string]interface{}{"list": []interface{}{"1", 1.5}})
+	schema.Validate(ctx)
+	validationErr, ok = ctx.Err.(*ValidationError)
+	if !ok || validationErr.Path != "list.1" || validationErr.Rule != "number.integer" {
+		t.Error("should throw the error of the first schema")
+	}
+
+	ctx = NewContext(map[string]interface{}{"list": []interface{}{true, 1, false}}, AbortEarly(false))
+	schema.Validate(ctx)
+	errs, ok := ctx.Err.(ValidationErrors)
+	if !ok || len(errs) != 2 || errs[0].Path != "list.0" || errs[1].Path != "list.2" {
+		t.Error("should throw the error of each item")
+	}
+}
+
+func TestArraySchema_ItemsTransform(t *testing.T) {
+	data := []byte(`{"tags": [" Go ", "JIO", null], "ids": ["1", 2], "points": [{"x": 1}, {}]}`)
+	_, err := ValidateJSON(&data, Object().Keys(K{
+		"tags": Array().Items(String().Trim().Lowercase().Default("none")),
+		"ids":  Array().Items(Number().ParseString()),
+		"points": Array().Items(Object().Keys(K{
+			"x": Number().Default(0),
+			"y": Number().Default(0),
+		})),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"ids":[1,2],"points":[{"x":1,"y":0},{"x":0,"y":0}],"tags":["go","jio","none"]}` {
+		t.Errorf("should write back the items, got %s", data)
+	}
+
+	ctx := NewContext([]int{1, 2})
+	Array().Items(Number()).Validate(ctx)
+	if ctx.Err != nil || !reflect.DeepEqual(ctx.Value, []int{1, 2}) {
+		t.Error("should keep the items of unassignable type")
+	}
+}
+
+func TestArraySchema_ItemsFailedSchema(t *testing.T) {
+	schema := Array().Items(
+		Object().Keys(K{"a": String().Default("leaked"), "b": Number().Required()}),
+		Object().Keys(K{"c": String()}),
+	)
+	ctx := NewContext([]interface{}{map[string]interface{}{"c": "x"}})
+	schema.Validate(ctx)
+	if ctx.Err != nil || !reflect.DeepEqual(ctx.Value, []interface{}{map[string]interface{}{"c": "x"}}) {
+		t.Error("should not keep the changes of the failed schema", ctx.Value)
+	}
+}
@@ -1,14 +1,8 @@
 package jio
 
-import (
-	"fmt"
-)
-
 // Bool Generates a schema object that matches bool data type
 func Bool() *BoolSchema {
-	return &BoolSchema{
-		rules: make([]func(*Context), 0, 3),
-	}
+	return &BoolSchema{}
 }
 
 var _ Schema = new(BoolSchema)
@@ -16,106 +10,205 @@ var _ Schema = new(BoolSchema)
 // BoolSchema match bool data type
 type BoolSchema struct {
 	baseSchema
+}
 
-	required *bool
-	rules    []func(*Context)
+func (b *BoolSchema) clone() *BoolSchema {
+	return &BoolSchema{baseSchema: b.baseSchema.clone()}
 }
 
 // SetPriority same as AnySchema.SetPriority
 func (b *BoolSchema) SetPriority(priority int) *BoolSchema {
-	b.priority = priority
-	return b
+	schema := b.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (b *BoolSchema) Messages(messages Messages) *BoolSchema {
+	schema := b.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (b *BoolSchema) Meta(key string, value interface{}) *BoolSchema {
+	schema := b.clone()
+	schema.setMeta(key, value)
+	return schema
 }
 
 // PrependTransform same as AnySchema.PrependTransform
 func (b *BoolSchema) PrependTransform(f func(*Context)) *BoolSchema {
-	b.rules = append([]func(*Context){f}, b.rules...)
-	return b
+	schema := b.clone()
+	schema.prependRule(f)
+	return schema
 }
 
 // Transform same as AnySchema.Transform
 func (b *BoolSchema) Transform(f func(*Context)) *BoolSchema {
-	b.rules = append(b.rules, f)
-	return b
+	schema := b.clone()
+	schema.appendRule(f)
+	return schema
 }
 
 // Required same as AnySchema.Required
 func (b *BoolSchema) Required() *BoolSchema {
-	b.required = boolPtr(true)
-	return b.PrependTransform(func(ctx *Context) {
+	schema := b.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Abort(fmt.Errorf("field `%s` is required", ctx.FieldPath()))
+			ctx.abortRule("any.required", nil)
 		}
 	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
 }
 
 // Optional same as AnySchema.Optional
 func (b *BoolSchema) Optional() *BoolSchema {
-	b.required = boolPtr(false)
-	return b.PrependTransform(func(ctx *Context) {
+	schema := b.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Skip()
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
 }
 
 // Default same as AnySchema.Default
 func (b *BoolSchema) Default(value bool) *BoolSchema {
-	b.required = boolPtr(false)
-	return b.PrependTransform(func(ctx *Context) {
+	schema := b.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Value = value
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Set same as AnySchema.Set
 func (b *BoolSchema) Set(value bool) *BoolSchema {
-	return b.Transform(func(ctx *Context) {
+	schema := b.Transform(func(ctx *Context) {
 		ctx.Value = value
 	})
+	schema.describeLast("set", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Equal same as AnySchema.Equal
 func (b *BoolSchema) Equal(value bool) *BoolSchema {
-	return b.Transform(func(ctx *Context) {
+	schema := b.Transform(func(ctx *Context) {
 		if value != ctx.Value {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not %v", ctx.FieldPath(), ctx.Value, value))
+			ctx.failRule("bool.equal", map[string]interface{}{"expected": value})
 		}
 	})
+	schema.describeLast("equal", map[string]interface{}{"expected": value})
+	return schema
 }
 
 // When same as AnySchema.When
 func (b *BoolSchema) When(refPath string, condition interface{}, then Schema) *BoolSchema {
-	return b.Transform(func(ctx *Context) { b.when(ctx, refPath, condition, then) })
+	schema := b.Transform(func(ctx *Context) { b.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
+}
+
+// Check use the provided function to validate the value of the key.
+// Throws an error when the value is not bool.
+func (b *BoolSchema) Check(f func(bool) error) *BoolSchema {
+	schema := b.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(bool)
+		if !ok {
+			ctx.abortRule("bool.base", nil)
+			return
+		}
+		if err := f(ctxValue); err != nil {
+			ctx.failCheck("bool.check", err)
+		}
+	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// Valid same as AnySchema.Valid
+func (b *BoolSchema) Valid(values ...bool) *BoolSchema {
+	schema := b.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(bool)
+		if !ok {
+			ctx.abortRule("bool.base", nil)
+			return
+		}
+		for _, v := range values {
+			if v == ctxValue {
+				return
+			}
+		}
+		ctx.failRule("bool.valid", map[string]interface{}{"valids": values})
+	})
+	schema.describeLast("valid", map[string]interface{}{"valids": values})
+	return schema
+}
+
+// Convert use the provided function to convert the value of the key.
+// Throws an error when the value is not bool.
+func (b *BoolSchema) Convert(f func(bool) bool) *BoolSchema {
+	schema := b.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(bool)
+		if !ok {
+			ctx.abortRule("bool.base", nil)
+			return
+		}
+		if value := f(ctxValue); value != ctxValue {
+			ctx.Value = value
+		}
+	})
+	schema.describeLast("convert", nil)
+	return schema
 }
 
 // Truthy allow for additional values to be considered valid booleans by converting them to true during validation.
 func (b *BoolSchema) Truthy(values ...interface{}) *BoolSchema {
-	return b.Transform(func(ctx *Context) {
+	schema := b.Transform(func(ctx *Context) {
 		for _, v := range values {
 			if v == ctx.Value {
 				ctx.Value = true
 			}
 		}
 	})
+	schema.describeLast("truthy", map[string]interface{}{"values": values})
+	return schema
 }
 
 // Falsy allow for additional values to be considered valid booleans by converting them to false during validation.
 func (b *BoolSchema) Falsy(values ...interface{}) *BoolSchema {
-	return b.Transform(func(ctx *Context) {
+	schema := b.Transform(func(ctx *Context) {
 		for _, v := range values {
 			if v == ctx.Value {
 				ctx.Value = false
 			}
 		}
 	})
+	schema.describeLast("falsy", map[string]interface{}{"values": values})
+	return schema
+}
+
+// Describe same as AnySchema.Describe
+func (b *BoolSchema) Describe() *Description {
+	return b.describe("bool")
 }
 
 // Validate same as AnySchema.Validate
 func (b *BoolSchema) Validate(ctx *Context) {
-	if b.required == nil {
-		b.Optional()
+	if b.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if b.messages != nil {
+		ctx.pushMessages(b.messages)
+		defer ctx.popMessages()
 	}
 	for _, rule := range b.rules {
 		rule(ctx)
@@ -123,9 +216,7 @@ func (b *BoolSchema) Validate(ctx *Context) {
 			return
 		}
 	}
-	if ctx.Err == nil {
-		if _, ok := (ctx.Value).(bool); !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not boolean", ctx.FieldPath(), ctx.Value))
-		}
+	if _, ok := (ctx.Value).(bool); !ok {
+		ctx.abortRule("bool.base", nil)
 	}
 }
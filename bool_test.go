@@ -141,3 +141,38 @@ func TestBoolSchema_Validate(t *testing.T) {
 		t.Error("default optional should no error")
 	}
 }
+
+func TestBoolSchema_Check(t *testing.T) {
+	schema := Bool().Check(func(value bool) error {
+		if !value {
+			return errors.New("must be accepted")
+		}
+		return nil
+	})
+	ctx := NewContext(false)
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.(*ValidationError).Rule != "bool.check" {
+		t.Error("test check failed")
+	}
+	ctx = NewContext("true")
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.(*ValidationError).Rule != "bool.base" {
+		t.Error("test check failed")
+	}
+}
+
+func TestBoolSchema_Valid(t *testing.T) {
+	ctx := NewContext(false)
+	Bool().Valid(true).Validate(ctx)
+	if ctx.Err == nil || ctx.Err.(*ValidationError).Rule != "bool.valid" {
+		t.Error("test valid failed")
+	}
+}
+
+func TestBoolSchema_Convert(t *testing.T) {
+	ctx := NewContext(false)
+	Bool().Convert(func(value bool) bool { return !value }).Validate(ctx)
+	if ctx.Value != true {
+		t.Error("test convert failed")
+	}
+}
@@ -0,0 +1,303 @@
+package jio
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var _ Schema = new(ByteSizeSchema)
+
+// byteSizeUnits are the multiples of the units, the SI units such as `MB` are the powers of 1000,
+// and the IEC units such as `MiB` are the powers of 1024. The units are case-insensitive.
+var byteSizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "ki": 1 << 10, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mi": 1 << 20, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gi": 1 << 30, "gib": 1 << 30,
+	"t": 1e12, "tb": 1e12, "ti": 1 << 40, "tib": 1 << 40,
+	"p": 1e15, "pb": 1e15, "pi": 1 << 50, "pib": 1 << 50,
+	"e": 1e18, "eb": 1e18, "ei": 1 << 60, "eib": 1 << 60,
+}
+
+// byteSizeNames are the IEC units used to format the sizes, from the largest.
+var byteSizeNames = []string{"EiB", "PiB", "TiB", "GiB", "MiB", "KiB"}
+
+var byteSizeRegex = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+
+// ByteSize Generates a schema object that matches the sizes in bytes, the strings such as `512MiB`, `1.5GB` or `1024`,
+// and the non-negative integers of bytes are accepted.
+// The value is converted to int64 bytes by the first rule, so that it's an int64 in the rules added later,
+// and it's written back as an int64 number of bytes, or a json.Number when UseNumber is set, unless Output is set.
+func ByteSize() *ByteSizeSchema {
+	schema := &ByteSizeSchema{output: ByteSizeBytes}
+	schema.appendRule(func(ctx *Context) {
+		if ctxValue, ok := ctx.Value.(string); ok {
+			value, ok := parseByteSize(ctxValue)
+			if !ok {
+				ctx.abortRule("byteSize.parse", nil)
+				return
+			}
+			ctx.Value = value
+			return
+		}
+		value, ok := byteSizeNumber(ctx.Value)
+		if !ok {
+			ctx.abortRule("byteSize.base", nil)
+			return
+		}
+		ctx.Value = value
+	})
+	schema.describeLast("parse", nil)
+	return schema
+}
+
+// The output formats of ByteSizeSchema.Output.
+const (
+	// ByteSizeBytes outputs the bytes as int64, or json.Number when UseNumber is set, it's the default output.
+	ByteSizeBytes = "bytes"
+	// ByteSizeString outputs the strings with the largest IEC unit dividing the size, such as `512MiB` or `1500B`.
+	ByteSizeString = "string"
+)
+
+// ByteSizeSchema match the sizes in bytes.
+type ByteSizeSchema struct {
+	baseSchema
+	output string
+}
+
+func (b *ByteSizeSchema) clone() *ByteSizeSchema {
+	schema := *b
+	schema.baseSchema = b.baseSchema.clone()
+	return &schema
+}
+
+// SetPriority same as AnySchema.SetPriority
+func (b *ByteSizeSchema) SetPriority(priority int) *ByteSizeSchema {
+	schema := b.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (b *ByteSizeSchema) Messages(messages Messages) *ByteSizeSchema {
+	schema := b.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (b *ByteSizeSchema) Meta(key string, value interface{}) *ByteSizeSchema {
+	schema := b.clone()
+	schema.setMeta(key, value)
+	return schema
+}
+
+// PrependTransform same as AnySchema.PrependTransform, the function receives the value before the conversion.
+func (b *ByteSizeSchema) PrependTransform(f func(*Context)) *ByteSizeSchema {
+	schema := b.clone()
+	schema.prependRule(f)
+	return schema
+}
+
+// Transform same as AnySchema.Transform
+func (b *ByteSizeSchema) Transform(f func(*Context)) *ByteSizeSchema {
+	schema := b.clone()
+	schema.appendRule(f)
+	return schema
+}
+
+// Required same as AnySchema.Required
+func (b *ByteSizeSchema) Required() *ByteSizeSchema {
+	schema := b.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.abortRule("any.required", nil)
+		}
+	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
+}
+
+// Optional same as AnySchema.Optional
+func (b *ByteSizeSchema) Optional() *ByteSizeSchema {
+	schema := b.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Skip()
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
+}
+
+// Default same as AnySchema.Default
+func (b *ByteSizeSchema) Default(value int64) *ByteSizeSchema {
+	schema := b.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Value = value
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
+}
+
+// When same as AnySchema.When
+func (b *ByteSizeSchema) When(refPath string, condition interface{}, then Schema) *ByteSizeSchema {
+	schema := b.Transform(func(ctx *Context) { b.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
+}
+
+// Check use the provided function to validate the value of the key.
+// Throws an error when the value is not a size.
+func (b *ByteSizeSchema) Check(f func(int64) error) *ByteSizeSchema {
+	schema := b.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(int64)
+		if !ok {
+			ctx.abortRule("byteSize.base", nil)
+			return
+		}
+		if err := f(ctxValue); err != nil {
+			ctx.failCheck("byteSize.check", err)
+		}
+	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// Min check if the size is greater than or equal to the limit in bytes.
+func (b *ByteSizeSchema) Min(min int64) *ByteSizeSchema {
+	return b.check("byteSize.min", min, func(value int64) bool { return value >= min })
+}
+
+// Max check if the size is less than or equal to the limit in bytes.
+func (b *ByteSizeSchema) Max(max int64) *ByteSizeSchema {
+	return b.check("byteSize.max", max, func(value int64) bool { return value <= max })
+}
+
+func (b *ByteSizeSchema) check(rule string, limit int64, f func(int64) bool) *ByteSizeSchema {
+	schema := b.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(int64)
+		if !ok {
+			ctx.abortRule("byteSize.base", nil)
+			return
+		}
+		if !f(ctxValue) {
+			ctx.failRule(rule, map[string]interface{}{"limit": formatByteSize(limit)})
+		}
+	})
+	schema.describeLast(rule, map[string]interface{}{"limit": limit})
+	return schema
+}
+
+// Output set the format of the value written back, it's ByteSizeBytes or ByteSizeString.
+func (b *ByteSizeSchema) Output(format string) *ByteSizeSchema {
+	if format != ByteSizeBytes && format != ByteSizeString {
+		panic("jio: the byte size output must be ByteSizeBytes or ByteSizeString")
+	}
+	schema := b.clone()
+	schema.output = format
+	return schema
+}
+
+// Describe same as AnySchema.Describe, the output is described in the flags.
+func (b *ByteSizeSchema) Describe() *Description {
+	description := b.describe("byteSize")
+	if description.Flags == nil {
+		description.Flags = map[string]interface{}{}
+	}
+	description.Flags["output"] = b.output
+	return description
+}
+
+// Validate same as AnySchema.Validate, the value is converted to the output format after the rules.
+func (b *ByteSizeSchema) Validate(ctx *Context) {
+	if b.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if b.messages != nil {
+		ctx.pushMessages(b.messages)
+		defer ctx.popMessages()
+	}
+	for _, rule := range b.rules {
+		rule(ctx)
+		if ctx.skip {
+			return
+		}
+	}
+	value, ok := ctx.Value.(int64)
+	if !ok {
+		ctx.abortRule("byteSize.base", nil)
+		return
+	}
+	if b.output == ByteSizeString {
+		ctx.Value = formatByteSize(value)
+	} else if ctx.options.useNumber {
+		ctx.Value = json.Number(strconv.FormatInt(value, 10))
+	} else {
+		ctx.Value = value
+	}
+}
+
+// byteSizeNumber convert the non-negative integer to int64 bytes, the integers and json.Number are converted exactly.
+func byteSizeNumber(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, v >= 0
+	case int:
+		return int64(v), v >= 0
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case json.Number:
+		size, ok := new(big.Rat).SetString(string(v))
+		if !ok || !size.IsInt() || size.Sign() < 0 || !size.Num().IsInt64() {
+			return 0, false
+		}
+		return size.Num().Int64(), true
+	}
+	size, ok := jsonSchemaNumber(value)
+	if !ok || size < 0 || size != math.Trunc(size) || size >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(size), true
+}
+
+// parseByteSize parse the size such as `1.5GiB` exactly, the fractions must make a whole number of bytes.
+func parseByteSize(value string) (int64, bool) {
+	matches := byteSizeRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, false
+	}
+	unit, ok := byteSizeUnits[strings.ToLower(matches[2])]
+	if !ok {
+		return 0, false
+	}
+	size, ok := new(big.Rat).SetString(matches[1])
+	if !ok {
+		return 0, false
+	}
+	size.Mul(size, new(big.Rat).SetInt64(unit))
+	if !size.IsInt() || !size.Num().IsInt64() {
+		return 0, false
+	}
+	return size.Num().Int64(), true
+}
+
+// formatByteSize format the size with the largest IEC unit dividing it.
+func formatByteSize(value int64) string {
+	for i, name := range byteSizeNames {
+		unit := int64(1) << (10 * uint(len(byteSizeNames)-i))
+		if value != 0 && value%unit == 0 {
+			return strconv.FormatInt(value/unit, 10) + name
+		}
+	}
+	return strconv.FormatInt(value, 10) + "B"
+}
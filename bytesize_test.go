@@ -0,0 +1,144 @@
+package jio
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestByteSizeSchema_Parse(t *testing.T) {
+	cases := []struct {
+		value  interface{}
+		output interface{}
+	}{
+		{"512MiB", int64(536870912)},
+		{"1.5GB", int64(1.5e9)},
+		{"1.1kb", int64(1100)},
+		{"10 KiB", int64(10240)},
+		{"1024", int64(1024)},
+		{4096.0, int64(4096)},
+		{int64(1), int64(1)},
+		{"0.5B", nil},
+		{"1XB", nil},
+		{"-1KB", nil},
+		{"16EiB", nil},
+		{1.5, nil},
+		{-1.0, nil},
+		{true, nil},
+	}
+	for _, c := range cases {
+		ctx := NewContext(c.value)
+		ByteSize().Validate(ctx)
+		if c.output == nil && ctx.Err == nil || c.output != nil && (ctx.Err != nil || ctx.Value != c.output) {
+			t.Error("should parse the size", c.value, ctx.Value, ctx.Err)
+		}
+	}
+
+	ctx := NewContext("1 apple")
+	ByteSize().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "byteSize.parse" {
+		t.Error("should report the invalid string")
+	}
+	ctx = NewContext(1.5)
+	ByteSize().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "byteSize.base" {
+		t.Error("should report the invalid value")
+	}
+}
+
+func TestByteSizeSchema_Output(t *testing.T) {
+	for value, output := range map[string]string{"512MiB": "512MiB", "1GB": "1000000000B", "2048": "2KiB", "0": "0B"} {
+		ctx := NewContext(value)
+		ByteSize().Output(ByteSizeString).Validate(ctx)
+		if ctx.Err != nil || ctx.Value != output {
+			t.Error("should output the normalized string", value, ctx.Value)
+		}
+	}
+
+	data := []byte(`{"memory": "1GiB"}`)
+	_, err := ValidateJSON(&data, Object().Keys(K{"memory": ByteSize()}))
+	if err != nil || string(data) != `{"memory":1073741824}` {
+		t.Error("should write back the bytes", string(data))
+	}
+	data = []byte(`{"memory": "9007199254740993"}`)
+	_, err = ValidateJSON(&data, Object().Keys(K{"memory": ByteSize()}), UseNumber())
+	if err != nil || string(data) != `{"memory":9007199254740993}` {
+		t.Error("should write back the exact bytes", string(data))
+	}
+	data = []byte(`{"memory": 9007199254740993}`)
+	_, err = ValidateJSON(&data, Object().Keys(K{"memory": ByteSize()}), UseNumber())
+	if err != nil || string(data) != `{"memory":9007199254740993}` {
+		t.Error("should parse the numbers exactly", string(data))
+	}
+	for value, expected := range map[interface{}]interface{}{
+		uint64(1 << 62):                    int64(1 << 62),
+		1 << 40:                            int64(1 << 40),
+		json.Number("1e3"):                 int64(1000),
+		json.Number("1.5"):                 nil,
+		json.Number("-1"):                  nil,
+		uint64(math.MaxInt64) + 1:          nil,
+		json.Number("9223372036854775808"): nil,
+	} {
+		ctx := NewContext(value)
+		ByteSize().Validate(ctx)
+		if expected == nil && ctx.Err == nil || expected != nil && (ctx.Err != nil || ctx.Value != expected) {
+			t.Error("should convert the number exactly", value, ctx.Value, ctx.Err)
+		}
+	}
+	ctx := NewContext("1KiB", UseNumber())
+	ByteSize().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != json.Number("1024") {
+		t.Error("should write back json.Number when UseNumber is set", ctx.Value)
+	}
+}
+
+func TestByteSizeSchema_Required(t *testing.T) {
+	ctx := NewContext(nil)
+	ByteSize().Required().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" {
+		t.Error("should error when no data")
+	}
+	ctx = NewContext(nil)
+	ByteSize().Default(1 << 20).Output(ByteSizeString).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "1MiB" {
+		t.Error("should set the default value")
+	}
+}
+
+func TestByteSizeSchema_MinMax(t *testing.T) {
+	schema := ByteSize().Min(1 << 10).Max(1 << 30)
+	for value, rule := range map[string]string{"1KiB": "", "1GiB": "", "1023": "byteSize.min", "1.5GiB": "byteSize.max"} {
+		ctx := NewContext(value)
+		schema.Validate(ctx)
+		err, _ := ctx.Err.(*ValidationError)
+		if rule == "" && ctx.Err != nil || rule != "" && (err == nil || err.Rule != rule) {
+			t.Error("should check the limits", value, ctx.Err)
+		}
+	}
+	ctx := NewContext("2GiB")
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Args["limit"] != "1GiB" {
+		t.Error("should report the limit as a size")
+	}
+}
+
+func TestByteSizeSchema_Generate(t *testing.T) {
+	schema := Object().Keys(K{"memory": ByteSize().Required().Min(1 << 20).Max(1 << 30)})
+	for seed := int64(0); seed < 20; seed++ {
+		if _, err := Generate(schema, rand.NewSource(seed)); err != nil {
+			t.Error("should generate a valid size", err)
+		}
+	}
+	mutations, err := Mutate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Error("should mutate the size", err)
+	}
+	rules := map[string]bool{}
+	for _, mutation := range mutations {
+		rules[mutation.Rule] = true
+	}
+	if !rules["byteSize.base"] || !rules["byteSize.parse"] || !rules["byteSize.min"] || !rules["byteSize.max"] {
+		t.Error("should break every rule of the size", rules)
+	}
+}
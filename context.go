@@ -3,26 +3,100 @@ package jio
 import (
 	"reflect"
 	"strings"
+	"time"
 )
 
+// Option configures the validation, such as AbortEarly.
+type Option func(*options)
+
+type options struct {
+	abortEarly     bool
+	messages       Messages
+	acceptLanguage bool
+	anyRoot        bool
+	useNumber      bool
+	route          *openAPIRoute
+}
+
+func newOptions(opts []Option) options {
+	return options{abortEarly: true}.with(opts)
+}
+
+// with returns a copy of the options with the opts applied.
+func (o options) with(opts []Option) options {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// AbortEarly set whether to stop the validation on the first error, it's enabled by default.
+// When disabled, the validation keeps walking every key, every item and every rule,
+// and the Err of the context will be ValidationErrors which lists all errors.
+func AbortEarly(abortEarly bool) Option {
+	return func(o *options) {
+		o.abortEarly = abortEarly
+	}
+}
+
+// AnyRoot make ValidateBody accept any json root such as an array or a string, same as ValidateJSONValue.
+// The validated value is saved to the request's context with ContextKeyBody.
+func AnyRoot() Option {
+	return func(o *options) {
+		o.anyRoot = true
+	}
+}
+
+// UseNumber make ValidateJSON decode the json numbers into json.Number instead of float64,
+// so that the large integers such as 64-bit IDs are validated and written back without losing precision.
+// It also makes NumberSchema.ParseString produce json.Number.
+func UseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
 // NewContext Generates a context object with the provided data.
-func NewContext(data interface{}) *Context {
+func NewContext(data interface{}, opts ...Option) *Context {
 	return &Context{
-		root:   data,
-		Value:  data,
-		fields: make([]string, 0, 3),
+		root:    data,
+		Value:   data,
+		fields:  make([]string, 0, 3),
+		options: newOptions(opts),
 	}
 }
 
+// reset prepare the context for a new validation, the buffers are kept for reuse.
+func (ctx *Context) reset(data interface{}, o options) {
+	ctx.root = data
+	ctx.Value = data
+	ctx.Err = nil
+	ctx.fields = ctx.fields[:0]
+	ctx.storage = nil
+	ctx.skip = false
+	ctx.options = o
+	ctx.errors = nil
+	ctx.depth = 0
+	ctx.dates = nil
+	ctx.schemaMessages = ctx.schemaMessages[:0]
+}
+
 // Context contains data and toolkit
 type Context struct {
-	Value     interface{}
-	Err       error
-	root      interface{}
-	fields    []string
-	storage   map[string]interface{}
-	skip      bool
-	kindCache map[*interface{}]reflect.Kind
+	Value   interface{}
+	Err     error
+	root    interface{}
+	fields  []string
+	storage map[string]interface{}
+	skip    bool
+	options options
+	errors  ValidationErrors
+	// depth is the number of the Link schemas being validated.
+	depth int
+	// dates is the parsed values of the Date schemas by the field path, for the limits referencing them.
+	dates map[string]time.Time
+
+	schemaMessages []Messages
 }
 
 // Ref return the reference value.
@@ -50,11 +124,87 @@ func (ctx *Context) FieldPath() string {
 }
 
 // Abort throw an error and skip the following check rules.
+// The error will be wrapped as a ValidationError with the `any.custom` rule unless it is already one.
+// When AbortEarly is disabled, the error is collected and the validation of other values goes on.
 func (ctx *Context) Abort(err error) {
-	ctx.Err = err
+	ctx.addError(err)
 	ctx.skip = true
 }
 
+// abortRule throw a ValidationError of the rule and skip the following check rules.
+func (ctx *Context) abortRule(rule string, args map[string]interface{}) {
+	ctx.Abort(ctx.newError(rule, args))
+}
+
+// failRule throw a ValidationError of the rule.
+// Unlike abortRule, the following check rules still run when AbortEarly is disabled.
+func (ctx *Context) failRule(rule string, args map[string]interface{}) {
+	ctx.fail(ctx.newError(rule, args))
+}
+
+// failCheck throw the error returned by a Check function as a ValidationError of the rule.
+func (ctx *Context) failCheck(rule string, err error) {
+	validationErr := ctx.newError(rule, map[string]interface{}{"error": err.Error()})
+	validationErr.err = err
+	ctx.fail(validationErr)
+}
+
+func (ctx *Context) fail(err *ValidationError) {
+	if ctx.options.abortEarly {
+		ctx.Abort(err)
+		return
+	}
+	ctx.addError(err)
+}
+
+func (ctx *Context) addError(err error) {
+	switch e := err.(type) {
+	case nil:
+		if ctx.options.abortEarly {
+			ctx.Err = nil
+		}
+		return
+	case ValidationErrors:
+		ctx.errors = append(ctx.errors, e...)
+	default:
+		ctx.errors = append(ctx.errors, ctx.wrapError(err))
+	}
+	ctx.resetErrors(len(ctx.errors))
+}
+
+// resetErrors drop the errors after the first n errors, and set Err to the remaining errors.
+func (ctx *Context) resetErrors(n int) {
+	ctx.errors = ctx.errors[:n]
+	switch {
+	case n == 0:
+		ctx.Err = nil
+	case ctx.options.abortEarly:
+		ctx.Err = ctx.errors[n-1]
+	default:
+		ctx.Err = ctx.errors
+	}
+}
+
+func (ctx *Context) wrapError(err error) *ValidationError {
+	if validationErr, ok := err.(*ValidationError); ok {
+		return validationErr
+	}
+	validationErr := ctx.newError("any.custom", map[string]interface{}{"error": err.Error()})
+	validationErr.err = err
+	return validationErr
+}
+
+func (ctx *Context) newError(rule string, args map[string]interface{}) *ValidationError {
+	path := ctx.FieldPath()
+	return &ValidationError{
+		Path:    path,
+		Rule:    rule,
+		Value:   ctx.Value,
+		Args:    args,
+		Message: renderMessage(ctx.message(rule), path, ctx.Value, args),
+	}
+}
+
 // Skip the following check rules.
 func (ctx *Context) Skip() {
 	ctx.skip = true
@@ -77,15 +227,8 @@ func (ctx *Context) Get(name string) (interface{}, bool) {
 	return value, ok
 }
 
-// AssertKind assert the value type and cache.
+// AssertKind assert the kind of the value, returns false when the value is nil.
 func (ctx *Context) AssertKind(kind reflect.Kind) bool {
-	if ctx.kindCache == nil {
-		ctx.kindCache = make(map[*interface{}]reflect.Kind)
-	}
-	cachedKind, ok := ctx.kindCache[&ctx.Value]
-	if !ok {
-		cachedKind = reflect.TypeOf(ctx.Value).Kind()
-		ctx.kindCache[&ctx.Value] = cachedKind
-	}
-	return cachedKind == kind
+	valueType := reflect.TypeOf(ctx.Value)
+	return valueType != nil && valueType.Kind() == kind
 }
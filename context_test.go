@@ -112,13 +112,38 @@ func TestContext_AssertKind(t *testing.T) {
 	if !ctx.AssertKind(reflect.String) {
 		t.Error("assert string faild")
 	}
-	if len(ctx.kindCache) != 1 {
-		t.Error("assert string faild")
+	ctx.Value = []int{1}
+	if ctx.AssertKind(reflect.String) || !ctx.AssertKind(reflect.Slice) {
+		t.Error("assert slice faild")
 	}
-	if !ctx.AssertKind(reflect.String) {
-		t.Error("assert string faild")
+	ctx.Value = nil
+	if ctx.AssertKind(reflect.String) {
+		t.Error("assert nil faild")
 	}
-	if len(ctx.kindCache) != 1 {
-		t.Error("assert string faild")
+}
+
+func TestContext_AbortEarly(t *testing.T) {
+	ctx := NewContext(nil, AbortEarly(false))
+	ctx.failRule("any.custom", map[string]interface{}{"error": "1"})
+	if ctx.skip {
+		t.Error("should not skip")
+	}
+	ctx.Abort(errors.New("2"))
+	if !ctx.skip {
+		t.Error("should skip")
+	}
+	errs, ok := ctx.Err.(ValidationErrors)
+	if !ok || len(errs) != 2 || errs.Error() != "1; 2" {
+		t.Error("should collect errors")
+	}
+	var validationErr *ValidationError
+	if !errors.As(ctx.Err, &validationErr) || validationErr.Error() != "1" {
+		t.Error("should find the first error")
+	}
+
+	ctx = NewContext(nil)
+	ctx.failRule("any.custom", map[string]interface{}{"error": "1"})
+	if !ctx.skip {
+		t.Error("should abort early")
 	}
 }
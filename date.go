@@ -0,0 +1,374 @@
+package jio
+
+import (
+	"math"
+	"time"
+)
+
+var _ Schema = new(DateSchema)
+
+// Date Generates a schema object that matches the dates, the RFC 3339 strings such as `2006-01-02T15:04:05Z`
+// and the time.Time values are accepted by default, see Layouts and Timestamp for the other formats.
+// The value is a time.Time in the rules such as Transform and Check,
+// and it's written back as a RFC 3339 string unless Output is set.
+func Date() *DateSchema {
+	return &DateSchema{output: DateRFC3339}
+}
+
+// The output formats of DateSchema.Output, any other format is used as a layout of time.Format.
+const (
+	// DateRFC3339 outputs the RFC 3339 strings with the fractional seconds if any, it's the default output.
+	DateRFC3339 = "rfc3339"
+	// DateUnix outputs the Unix seconds as float64.
+	DateUnix = "unix"
+	// DateUnixMilli outputs the Unix milliseconds as float64.
+	DateUnixMilli = "unixMilli"
+	// DateTime outputs the time.Time values, which are encoded as RFC 3339 strings by encoding/json.
+	DateTime = "time"
+)
+
+// DateSchema match the dates.
+type DateSchema struct {
+	baseSchema
+	layouts   []string
+	timestamp string
+	location  *time.Location
+	output    string
+}
+
+func (d *DateSchema) clone() *DateSchema {
+	schema := *d
+	schema.baseSchema = d.baseSchema.clone()
+	return &schema
+}
+
+// SetPriority same as AnySchema.SetPriority
+func (d *DateSchema) SetPriority(priority int) *DateSchema {
+	schema := d.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (d *DateSchema) Messages(messages Messages) *DateSchema {
+	schema := d.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (d *DateSchema) Meta(key string, value interface{}) *DateSchema {
+	schema := d.clone()
+	schema.setMeta(key, value)
+	return schema
+}
+
+// PrependTransform same as AnySchema.PrependTransform
+func (d *DateSchema) PrependTransform(f func(*Context)) *DateSchema {
+	schema := d.clone()
+	schema.prependRule(f)
+	return schema
+}
+
+// Transform same as AnySchema.Transform
+func (d *DateSchema) Transform(f func(*Context)) *DateSchema {
+	schema := d.clone()
+	schema.appendRule(f)
+	return schema
+}
+
+// Required same as AnySchema.Required
+func (d *DateSchema) Required() *DateSchema {
+	schema := d.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.abortRule("any.required", nil)
+		}
+	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
+}
+
+// Optional same as AnySchema.Optional
+func (d *DateSchema) Optional() *DateSchema {
+	schema := d.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Skip()
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
+}
+
+// Default same as AnySchema.Default
+func (d *DateSchema) Default(value time.Time) *DateSchema {
+	schema := d.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Value = value
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
+}
+
+// When same as AnySchema.When
+func (d *DateSchema) When(refPath string, condition interface{}, then Schema) *DateSchema {
+	schema := d.Transform(func(ctx *Context) { d.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
+}
+
+// Check use the provided function to validate the value of the key.
+// Throws an error when the value is not a date.
+func (d *DateSchema) Check(f func(time.Time) error) *DateSchema {
+	schema := d.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(time.Time)
+		if !ok {
+			ctx.abortRule("date.base", nil)
+			return
+		}
+		if err := f(ctxValue); err != nil {
+			ctx.failCheck("date.check", err)
+		}
+	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// Layouts accept the strings in the layouts of time.Parse, which are tried in order after RFC 3339.
+// The strings without a time zone are parsed in the location of In, UTC by default.
+// Like Timestamp and In, it also applies to the dates referenced by the limits of the checks added after it.
+func (d *DateSchema) Layouts(layouts ...string) *DateSchema {
+	schema := d.clone()
+	schema.layouts = append(d.layouts[:len(d.layouts):len(d.layouts)], layouts...)
+	return schema
+}
+
+// Timestamp accept the numbers as Unix timestamps, the unit is DateUnix for seconds or DateUnixMilli for milliseconds.
+// The numbers are rejected by default, the timestamps are in UTC unless the location is set by In.
+func (d *DateSchema) Timestamp(unit string) *DateSchema {
+	if unit != DateUnix && unit != DateUnixMilli {
+		panic("jio: the timestamp unit must be DateUnix or DateUnixMilli")
+	}
+	schema := d.clone()
+	schema.timestamp = unit
+	return schema
+}
+
+// In normalize the dates to the location, such as time.UTC.
+func (d *DateSchema) In(location *time.Location) *DateSchema {
+	schema := d.clone()
+	schema.location = location
+	return schema
+}
+
+// UTC same as In(time.UTC)
+func (d *DateSchema) UTC() *DateSchema {
+	return d.In(time.UTC)
+}
+
+// Output set the format of the value written back, it's DateRFC3339, DateUnix, DateUnixMilli, DateTime
+// or a layout of time.Format.
+func (d *DateSchema) Output(format string) *DateSchema {
+	schema := d.clone()
+	schema.output = format
+	return schema
+}
+
+// Min check if the date is not before the limit.
+// The limit is a time.Time, a time.Duration relative to the time of the validation such as `-24 * time.Hour`,
+// or the reference path of another date, the check is skipped when the referenced date is absent,
+// and throws an error of the rule when the referenced value is not a date.
+// Under the same object, the key is validated after the referenced key,
+// so the limit is the referenced date before it's converted to the output format.
+func (d *DateSchema) Min(limit interface{}) *DateSchema {
+	return d.compare("date.min", limit, func(value, limit time.Time) bool { return !value.Before(limit) })
+}
+
+// Max check if the date is not after the limit, see Min for the limit.
+func (d *DateSchema) Max(limit interface{}) *DateSchema {
+	return d.compare("date.max", limit, func(value, limit time.Time) bool { return !value.After(limit) })
+}
+
+// Before check if the date is before the limit, see Min for the limit.
+func (d *DateSchema) Before(limit interface{}) *DateSchema {
+	return d.compare("date.before", limit, func(value, limit time.Time) bool { return value.Before(limit) })
+}
+
+// After check if the date is after the limit, see Min for the limit.
+func (d *DateSchema) After(limit interface{}) *DateSchema {
+	return d.compare("date.after", limit, func(value, limit time.Time) bool { return value.After(limit) })
+}
+
+func (d *DateSchema) compare(rule string, limit interface{}, f func(value, limit time.Time) bool) *DateSchema {
+	refPath, isRef := limit.(string)
+	switch limit.(type) {
+	case time.Time, time.Duration, string:
+	default:
+		panic("jio: the date limit must be a time.Time, a time.Duration or a reference path")
+	}
+	schema := d.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(time.Time)
+		if !ok {
+			ctx.abortRule("date.base", nil)
+			return
+		}
+		limitTime, ok, invalid := d.limit(ctx, limit)
+		if !ok {
+			return
+		}
+		if invalid {
+			ctx.failRule(rule, map[string]interface{}{"limit": refPath})
+			return
+		}
+		if !f(ctxValue, limitTime) {
+			ctx.failRule(rule, map[string]interface{}{"limit": limitTime.Format(time.RFC3339Nano)})
+		}
+	})
+	if isRef {
+		schema.addRefs(refPath)
+	}
+	schema.describeLast(rule, map[string]interface{}{"limit": limit})
+	return schema
+}
+
+// limit resolve the limit of the checks at the time of the validation, ok is false when the referenced date is absent
+// and invalid is true when the referenced value can't be parsed.
+// A reference to a validated Date schema uses its parsed date, as the value may have been converted by its Output.
+func (d *DateSchema) limit(ctx *Context, limit interface{}) (result time.Time, ok, invalid bool) {
+	switch v := limit.(type) {
+	case time.Time:
+		return v, true, false
+	case time.Duration:
+		return time.Now().Add(v), true, false
+	}
+	refPath := limit.(string)
+	value, ok := ctx.Ref(refPath)
+	if !ok || value == nil {
+		return time.Time{}, false, false
+	}
+	if result, ok := ctx.dates[refPath]; ok {
+		return result, true, false
+	}
+	result, ok = d.parse(value)
+	return result, true, !ok
+}
+
+// parse convert the value to time.Time in the location of the schema.
+func (d *DateSchema) parse(value interface{}) (time.Time, bool) {
+	var result time.Time
+	switch v := value.(type) {
+	case time.Time:
+		result = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		for _, layout := range d.layouts {
+			if err == nil {
+				break
+			}
+			location := d.location
+			if location == nil {
+				location = time.UTC
+			}
+			parsed, err = time.ParseInLocation(layout, v, location)
+		}
+		if err != nil {
+			return time.Time{}, false
+		}
+		result = parsed
+	default:
+		if d.timestamp == "" {
+			return time.Time{}, false
+		}
+		number, ok := jsonSchemaNumber(value)
+		unit := 1.0
+		if d.timestamp == DateUnixMilli {
+			unit = 1000
+		}
+		// the dates after the year 292277026596 overflow the seconds of time.Time
+		if !ok || math.IsNaN(number) || math.Abs(number/unit) > 1e15 {
+			return time.Time{}, false
+		}
+		seconds := math.Floor(number / unit)
+		result = time.Unix(int64(seconds), int64(math.Round((number-seconds*unit)*1e9/unit))).UTC()
+	}
+	if d.location != nil {
+		result = result.In(d.location)
+	}
+	return result, true
+}
+
+// format convert the date to the value of the output format.
+func (d *DateSchema) format(value time.Time) interface{} {
+	switch d.output {
+	case DateRFC3339:
+		return value.Format(time.RFC3339Nano)
+	case DateUnix:
+		return float64(value.Unix()) + float64(value.Nanosecond())/1e9
+	case DateUnixMilli:
+		return float64(value.Unix()*1000) + float64(value.Nanosecond())/1e6
+	case DateTime:
+		return value
+	}
+	return value.Format(d.output)
+}
+
+// Describe same as AnySchema.Describe, the formats are described in the flags.
+func (d *DateSchema) Describe() *Description {
+	description := d.describe("date")
+	if description.Flags == nil {
+		description.Flags = map[string]interface{}{}
+	}
+	description.Flags["output"] = d.output
+	if len(d.layouts) > 0 {
+		description.Flags["layouts"] = d.layouts
+	}
+	if d.timestamp != "" {
+		description.Flags["timestamp"] = d.timestamp
+	}
+	if d.location != nil {
+		description.Flags["location"] = d.location.String()
+	}
+	return description
+}
+
+// Validate same as AnySchema.Validate, the value is converted to time.Time before the rules
+// and converted to the output format after the rules.
+func (d *DateSchema) Validate(ctx *Context) {
+	if d.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if d.messages != nil {
+		ctx.pushMessages(d.messages)
+		defer ctx.popMessages()
+	}
+	if ctx.Value != nil {
+		value, ok := d.parse(ctx.Value)
+		if !ok {
+			ctx.abortRule("date.base", nil)
+			return
+		}
+		ctx.Value = value
+	}
+	for _, rule := range d.rules {
+		rule(ctx)
+		if ctx.skip {
+			return
+		}
+	}
+	value, ok := ctx.Value.(time.Time)
+	if !ok {
+		ctx.abortRule("date.base", nil)
+		return
+	}
+	if ctx.dates == nil {
+		ctx.dates = make(map[string]time.Time)
+	}
+	ctx.dates[ctx.FieldPath()] = value
+	ctx.Value = d.format(value)
+}
@@ -0,0 +1,231 @@
+package jio
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestDateSchema_Parse(t *testing.T) {
+	ctx := NewContext("2020-01-02T03:04:05.5+08:00")
+	Date().UTC().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "2020-01-01T19:04:05.5Z" {
+		t.Error("should parse RFC 3339 and normalize the timezone")
+	}
+
+	schema := Date().Layouts("2006-01-02", "02/01/2006 15:04")
+	ctx = NewContext("02/01/2020 10:30")
+	schema.Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "2020-01-02T10:30:00Z" {
+		t.Error("should parse the custom layouts in UTC")
+	}
+	shanghai := time.FixedZone("CST", 8*3600)
+	ctx = NewContext("2020-01-02")
+	schema.In(shanghai).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "2020-01-02T00:00:00+08:00" {
+		t.Error("should parse the custom layouts in the location")
+	}
+
+	ctx = NewContext(1577836800.0)
+	Date().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "date.base" {
+		t.Error("should reject the numbers by default")
+	}
+	ctx = NewContext(1577836800.0)
+	Date().Timestamp(DateUnix).UTC().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "2020-01-01T00:00:00Z" {
+		t.Error("should parse the Unix seconds")
+	}
+	ctx = NewContext(1577836800123.0)
+	Date().Timestamp(DateUnixMilli).UTC().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "2020-01-01T00:00:00.123Z" {
+		t.Error("should parse the Unix milliseconds")
+	}
+	ctx = NewContext(1577836800.0)
+	Date().Timestamp(DateUnix).Output(DateTime).Validate(ctx)
+	if value, ok := ctx.Value.(time.Time); ctx.Err != nil || !ok || value.Location() != time.UTC {
+		t.Error("should parse the timestamps in UTC by default")
+	}
+
+	for _, value := range []interface{}{"2020-13-01T00:00:00Z", "jio", true, map[string]interface{}{}} {
+		ctx = NewContext(value)
+		Date().Validate(ctx)
+		if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "date.base" {
+			t.Error("should reject the invalid dates", value)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("should panic on the unknown timestamp unit")
+		}
+	}()
+	Date().Timestamp("nano")
+}
+
+func TestDateSchema_Output(t *testing.T) {
+	cases := []struct {
+		format string
+		value  interface{}
+	}{
+		{DateRFC3339, "2020-01-01T00:00:00.25Z"},
+		{DateUnix, 1577836800.25},
+		{DateUnixMilli, 1577836800250.0},
+		{DateTime, time.Date(2020, 1, 1, 0, 0, 0, 250000000, time.UTC)},
+		{"2006-01-02", "2020-01-01"},
+	}
+	for _, c := range cases {
+		ctx := NewContext("2020-01-01T00:00:00.25Z")
+		Date().Output(c.format).Validate(ctx)
+		if ctx.Err != nil || ctx.Value != c.value {
+			t.Error("should output the format", c.format, ctx.Value)
+		}
+	}
+
+	data := []byte(`{"at": 1577836800}`)
+	_, err := ValidateJSON(&data, Object().Keys(K{"at": Date().Timestamp(DateUnix).UTC()}))
+	if err != nil || string(data) != `{"at":"2020-01-01T00:00:00Z"}` {
+		t.Error("should write back the output by ValidateJSON", string(data))
+	}
+}
+
+func TestDateSchema_Required(t *testing.T) {
+	ctx := NewContext(nil)
+	Date().Required().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" {
+		t.Error("should error when no data")
+	}
+	ctx = NewContext(nil)
+	Date().Optional().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != nil {
+		t.Error("should no error")
+	}
+	ctx = NewContext(nil)
+	Date().Default(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "2020-01-01T00:00:00Z" {
+		t.Error("should set the default value")
+	}
+}
+
+func TestDateSchema_Limits(t *testing.T) {
+	limit := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		schema *DateSchema
+		value  string
+		rule   string
+	}{
+		{Date().Min(limit), "2020-01-01T00:00:00Z", ""},
+		{Date().Min(limit), "2019-12-31T23:59:59Z", "date.min"},
+		{Date().Max(limit), "2020-01-01T08:00:00+08:00", ""},
+		{Date().Max(limit), "2020-01-01T00:00:01Z", "date.max"},
+		{Date().After(limit), "2020-01-01T00:00:00Z", "date.after"},
+		{Date().Before(limit), "2020-01-01T00:00:00Z", "date.before"},
+		{Date().Before(time.Hour), time.Now().Format(time.RFC3339), ""},
+		{Date().After(time.Hour), time.Now().Format(time.RFC3339), "date.after"},
+		{Date().Min(-time.Hour), time.Now().Add(-2 * time.Hour).Format(time.RFC3339), "date.min"},
+	}
+	for _, c := range cases {
+		ctx := NewContext(c.value)
+		c.schema.Validate(ctx)
+		err, _ := ctx.Err.(*ValidationError)
+		if c.rule == "" && ctx.Err != nil || c.rule != "" && (err == nil || err.Rule != c.rule) {
+			t.Error("should check the limit", c.value, c.rule, ctx.Err)
+		}
+	}
+	ctx := NewContext("2019-01-01T00:00:00Z")
+	Date().Min(limit).Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Args["limit"] != "2020-01-01T00:00:00Z" {
+		t.Error("should report the limit")
+	}
+}
+
+func TestDateSchema_Ref(t *testing.T) {
+	schema := Object().Keys(K{
+		"start": Date().Required(),
+		"end":   Date().Required().After("start"),
+		"until": Date().Max("missing"),
+	})
+	ctx := NewContext(map[string]interface{}{"start": "2020-01-01T00:00:00Z", "end": "2020-01-02T00:00:00Z", "until": "2030-01-01T00:00:00Z"})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should pass the referenced limit", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"end": "2020-01-01T00:00:00Z", "start": "2020-01-01T00:00:00Z"})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "date.after" || err.Path != "end" {
+		t.Error("should check the referenced limit", ctx.Err)
+	}
+
+	schema = Object().Keys(K{
+		"start": Date().Output(DateUnix),
+		"end":   Date().After("start"),
+	})
+	ctx = NewContext(map[string]interface{}{"start": "2020-01-02T00:00:00Z", "end": "2020-01-01T00:00:00Z"})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "date.after" {
+		t.Error("should check the referenced date converted by its output", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"start": "2020-01-01T00:00:00Z", "end": "2020-01-02T00:00:00Z"})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should pass the referenced date converted by its output", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"start": "jio", "end": "2020-01-02T00:00:00Z"})
+	Object().Keys(K{"start": Any(), "end": Date().After("start")}).Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "date.after" {
+		t.Error("should reject the limit referencing an invalid date", ctx.Err)
+	}
+}
+
+func TestDateSchema_Check(t *testing.T) {
+	schema := Date().Check(func(value time.Time) error {
+		if value.Weekday() == time.Sunday {
+			return errors.New("is a sunday")
+		}
+		return nil
+	})
+	ctx := NewContext("2020-01-05T00:00:00Z")
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "date.check" {
+		t.Error("should check the value")
+	}
+	ctx = NewContext("2020-01-06T00:00:00Z")
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should no error")
+	}
+}
+
+func TestDateSchema_Describe(t *testing.T) {
+	description := Date().Layouts("2006-01-02").UTC().Min("start").Describe()
+	if description.Type != "date" || description.Flags["location"] != "UTC" || description.Rules[0].Name != "min" {
+		t.Error("should describe the date")
+	}
+	result := JSONSchema(Date().Required().Min(time.Now()))
+	if result["type"] != "string" || result["format"] != "date-time" || result["minLength"] != nil ||
+		len(result["x-jio-rules"].([]string)) != 1 {
+		t.Error("should export the date as a date-time string")
+	}
+}
+
+func TestDateSchema_Generate(t *testing.T) {
+	limit := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	schema := Object().Keys(K{"at": Date().Required().After(limit).Max(limit.Add(time.Hour))})
+	for seed := int64(0); seed < 20; seed++ {
+		if _, err := Generate(schema, rand.NewSource(seed)); err != nil {
+			t.Error("should generate a valid date", err)
+		}
+	}
+	mutations, err := Mutate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Error("should mutate the date", err)
+	}
+	rules := map[string]bool{}
+	for _, mutation := range mutations {
+		rules[mutation.Rule] = true
+	}
+	if !rules["date.base"] || !rules["date.after"] || !rules["date.max"] || !rules["any.required"] {
+		t.Error("should break every rule of the date", rules)
+	}
+}
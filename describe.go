@@ -0,0 +1,108 @@
+package jio
+
+// Description is the serializable description of a schema returned by Describe,
+// which can be used to build tools such as doc generators, exporters and diff tools.
+type Description struct {
+	// Type is any, string, number, bool, object, array or custom for the schemas implemented outside jio.
+	Type string `json:"type"`
+	// Flags are the states of the schema, such as `"presence": "required"` and `"priority": 1`.
+	Flags map[string]interface{} `json:"flags,omitempty"`
+	// Rules are the rules in the order of validation. The args referencing schemas are described too.
+	// The rules added by Transform, Check and Convert are opaque and only have the name.
+	Rules []RuleDescription `json:"rules,omitempty"`
+	// Keys are the descriptions of the object keys.
+	Keys map[string]*Description `json:"keys,omitempty"`
+	// Items are the descriptions of the array item schemas.
+	Items []*Description `json:"items,omitempty"`
+	// Messages are the message overrides of the schema.
+	Messages Messages `json:"messages,omitempty"`
+	// Metadata is attached by Meta.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// RuleDescription describes a rule, the name is the method adding it, such as min or regex,
+// and the args are named as the args of the error, such as limit or regex.
+type RuleDescription struct {
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// Describe returns the description of any schema, the schemas implemented outside jio are described as custom.
+func Describe(schema Schema) *Description {
+	if s, ok := schema.(interface{ Describe() *Description }); ok {
+		return s.Describe()
+	}
+	return &Description{Type: "custom"}
+}
+
+func (b *baseSchema) describe(typ string) *Description {
+	description := &Description{
+		Type:     typ,
+		Messages: b.messages,
+		Metadata: b.meta,
+	}
+	flags := map[string]interface{}{}
+	if b.required != nil {
+		if *b.required {
+			flags["presence"] = "required"
+		} else {
+			flags["presence"] = "optional"
+		}
+	}
+	if b.priority != 0 {
+		flags["priority"] = b.priority
+	}
+	if len(flags) > 0 {
+		description.Flags = flags
+	}
+
+	for _, desc := range b.descs {
+		rule := RuleDescription{Name: desc.name}
+		switch desc.name {
+		case "keys":
+			if description.Keys == nil {
+				description.Keys = map[string]*Description{}
+			}
+			for key, child := range desc.args["keys"].(K) {
+				description.Keys[key] = Describe(child)
+			}
+		case "items":
+			for _, item := range desc.args["schemas"].([]Schema) {
+				description.Items = append(description.Items, Describe(item))
+			}
+		default:
+			rule.Args = describeArgs(desc.args)
+		}
+		description.Rules = append(description.Rules, rule)
+	}
+	return description
+}
+
+// describeArgs replace the schemas in the args by their descriptions, so that the args can be serialized.
+func describeArgs(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		switch v := value.(type) {
+		case Schema:
+			result[key] = Describe(v)
+		case []Schema:
+			descriptions := make([]*Description, 0, len(v))
+			for _, schema := range v {
+				descriptions = append(descriptions, Describe(schema))
+			}
+			result[key] = descriptions
+		case map[string]Schema:
+			descriptions := make(map[string]*Description, len(v))
+			for name, schema := range v {
+				descriptions[name] = Describe(schema)
+			}
+			result[key] = descriptions
+		default:
+			result[key] = value
+		}
+	}
+	return result
+}
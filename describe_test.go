@@ -0,0 +1,73 @@
+package jio
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	schema := Object().Keys(K{
+		"name": String().Min(3).Regex("^[a-z]+$").Lowercase().Required().Meta("description", "user name"),
+		"tags": Array().Items(String(), Number().Integer()).Max(3),
+		"role": Any().When("name", "root", Any().Equal("admin")),
+	}).SetPriority(1).Messages(Messages{"any.required": "required"})
+
+	description := schema.Describe()
+	if description.Type != "object" || description.Flags["priority"] != 1 || description.Messages["any.required"] != "required" {
+		t.Error("should describe the object")
+	}
+
+	name := description.Keys["name"]
+	expectedRules := []RuleDescription{
+		{Name: "required"},
+		{Name: "min", Args: map[string]interface{}{"limit": 3}},
+		{Name: "regex", Args: map[string]interface{}{"regex": "^[a-z]+$"}},
+		{Name: "lowercase"},
+	}
+	if name.Type != "string" || name.Flags["presence"] != "required" || !reflect.DeepEqual(name.Rules, expectedRules) {
+		t.Errorf("unexpected description %+v", name)
+	}
+	if name.Metadata["description"] != "user name" {
+		t.Error("should describe the metadata")
+	}
+
+	tags := description.Keys["tags"]
+	if len(tags.Items) != 2 || tags.Items[1].Type != "number" || tags.Items[1].Rules[0].Name != "integer" {
+		t.Error("should describe the items")
+	}
+
+	when := description.Keys["role"].Rules[0]
+	if then, ok := when.Args["then"].(*Description); !ok || when.Args["ref"] != "name" || then.Rules[0].Args["expected"] != "admin" {
+		t.Error("should describe the schema args")
+	}
+
+	if _, err := json.Marshal(description); err != nil {
+		t.Error("should be serializable")
+	}
+}
+
+type customSchema struct{}
+
+func (customSchema) Priority() int     { return 0 }
+func (customSchema) Validate(*Context) {}
+
+func TestDescribe_Custom(t *testing.T) {
+	if Describe(customSchema{}).Type != "custom" {
+		t.Error("should describe custom schema")
+	}
+	if Describe(TypedNumber[int](Number())).Type != "number" {
+		t.Error("should describe the wrapped schema")
+	}
+}
+
+func TestAnySchema_Meta(t *testing.T) {
+	schema := Any().Meta("title", "a")
+	other := schema.Meta("title", "b")
+	if schema.Describe().Metadata["title"] != "a" || other.Describe().Metadata["title"] != "b" {
+		t.Error("should not modify the original schema")
+	}
+	if JSONSchema(other)["title"] != "b" {
+		t.Error("should export the annotations")
+	}
+}
@@ -0,0 +1,232 @@
+package jio
+
+import (
+	"math"
+	"time"
+)
+
+var _ Schema = new(DurationSchema)
+
+// Duration Generates a schema object that matches the durations, the strings of time.ParseDuration such as `1h30m`,
+// the numbers of seconds and the time.Duration values are accepted.
+// The value is converted to time.Duration by the first rule, so that it's a time.Duration in the rules added later,
+// and it's written back as a normalized string such as `1h30m0s` unless Output is set.
+func Duration() *DurationSchema {
+	schema := &DurationSchema{output: DurationString}
+	schema.appendRule(func(ctx *Context) {
+		switch ctxValue := ctx.Value.(type) {
+		case time.Duration:
+		case string:
+			value, err := time.ParseDuration(ctxValue)
+			if err != nil {
+				ctx.abortRule("duration.parse", nil)
+				return
+			}
+			ctx.Value = value
+		default:
+			seconds, ok := jsonSchemaNumber(ctxValue)
+			if !ok || math.IsNaN(seconds) || math.Abs(seconds) > math.MaxInt64/float64(time.Second) {
+				ctx.abortRule("duration.base", nil)
+				return
+			}
+			ctx.Value = time.Duration(math.Round(seconds * float64(time.Second)))
+		}
+	})
+	schema.describeLast("parse", nil)
+	return schema
+}
+
+// The output formats of DurationSchema.Output.
+const (
+	// DurationString outputs the strings of time.Duration.String such as `1h30m0s`, it's the default output.
+	DurationString = "string"
+	// DurationSeconds outputs the seconds as float64.
+	DurationSeconds = "seconds"
+	// DurationValue outputs the time.Duration values, which are encoded as nanoseconds by encoding/json.
+	DurationValue = "duration"
+)
+
+// DurationSchema match the durations.
+type DurationSchema struct {
+	baseSchema
+	output string
+}
+
+func (d *DurationSchema) clone() *DurationSchema {
+	schema := *d
+	schema.baseSchema = d.baseSchema.clone()
+	return &schema
+}
+
+// SetPriority same as AnySchema.SetPriority
+func (d *DurationSchema) SetPriority(priority int) *DurationSchema {
+	schema := d.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (d *DurationSchema) Messages(messages Messages) *DurationSchema {
+	schema := d.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (d *DurationSchema) Meta(key string, value interface{}) *DurationSchema {
+	schema := d.clone()
+	schema.setMeta(key, value)
+	return schema
+}
+
+// PrependTransform same as AnySchema.PrependTransform, the function receives the value before the conversion.
+func (d *DurationSchema) PrependTransform(f func(*Context)) *DurationSchema {
+	schema := d.clone()
+	schema.prependRule(f)
+	return schema
+}
+
+// Transform same as AnySchema.Transform
+func (d *DurationSchema) Transform(f func(*Context)) *DurationSchema {
+	schema := d.clone()
+	schema.appendRule(f)
+	return schema
+}
+
+// Required same as AnySchema.Required
+func (d *DurationSchema) Required() *DurationSchema {
+	schema := d.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.abortRule("any.required", nil)
+		}
+	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
+}
+
+// Optional same as AnySchema.Optional
+func (d *DurationSchema) Optional() *DurationSchema {
+	schema := d.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Skip()
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
+}
+
+// Default same as AnySchema.Default
+func (d *DurationSchema) Default(value time.Duration) *DurationSchema {
+	schema := d.PrependTransform(func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Value = value
+		}
+	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
+}
+
+// When same as AnySchema.When
+func (d *DurationSchema) When(refPath string, condition interface{}, then Schema) *DurationSchema {
+	schema := d.Transform(func(ctx *Context) { d.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
+}
+
+// Check use the provided function to validate the value of the key.
+// Throws an error when the value is not a duration.
+func (d *DurationSchema) Check(f func(time.Duration) error) *DurationSchema {
+	schema := d.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(time.Duration)
+		if !ok {
+			ctx.abortRule("duration.base", nil)
+			return
+		}
+		if err := f(ctxValue); err != nil {
+			ctx.failCheck("duration.check", err)
+		}
+	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// Min check if the duration is greater than or equal to the limit.
+func (d *DurationSchema) Min(min time.Duration) *DurationSchema {
+	return d.check("duration.min", min, func(value time.Duration) bool { return value >= min })
+}
+
+// Max check if the duration is less than or equal to the limit.
+func (d *DurationSchema) Max(max time.Duration) *DurationSchema {
+	return d.check("duration.max", max, func(value time.Duration) bool { return value <= max })
+}
+
+func (d *DurationSchema) check(rule string, limit time.Duration, f func(time.Duration) bool) *DurationSchema {
+	schema := d.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(time.Duration)
+		if !ok {
+			ctx.abortRule("duration.base", nil)
+			return
+		}
+		if !f(ctxValue) {
+			ctx.failRule(rule, map[string]interface{}{"limit": limit.String()})
+		}
+	})
+	schema.describeLast(rule, map[string]interface{}{"limit": limit})
+	return schema
+}
+
+// Output set the format of the value written back, it's DurationString, DurationSeconds or DurationValue.
+func (d *DurationSchema) Output(format string) *DurationSchema {
+	if format != DurationString && format != DurationSeconds && format != DurationValue {
+		panic("jio: the duration output must be DurationString, DurationSeconds or DurationValue")
+	}
+	schema := d.clone()
+	schema.output = format
+	return schema
+}
+
+// Describe same as AnySchema.Describe, the output is described in the flags.
+func (d *DurationSchema) Describe() *Description {
+	description := d.describe("duration")
+	if description.Flags == nil {
+		description.Flags = map[string]interface{}{}
+	}
+	description.Flags["output"] = d.output
+	return description
+}
+
+// Validate same as AnySchema.Validate, the value is converted to the output format after the rules.
+func (d *DurationSchema) Validate(ctx *Context) {
+	if d.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if d.messages != nil {
+		ctx.pushMessages(d.messages)
+		defer ctx.popMessages()
+	}
+	for _, rule := range d.rules {
+		rule(ctx)
+		if ctx.skip {
+			return
+		}
+	}
+	value, ok := ctx.Value.(time.Duration)
+	if !ok {
+		ctx.abortRule("duration.base", nil)
+		return
+	}
+	switch d.output {
+	case DurationSeconds:
+		ctx.Value = value.Seconds()
+	case DurationValue:
+		ctx.Value = value
+	default:
+		ctx.Value = value.String()
+	}
+}
@@ -0,0 +1,154 @@
+package jio
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestDurationSchema_Parse(t *testing.T) {
+	cases := []struct {
+		value  interface{}
+		output interface{}
+	}{
+		{"30s", "30s"},
+		{"1h30m", "1h30m0s"},
+		{" 90m", nil},
+		{90.0, "1m30s"},
+		{1.5, "1.5s"},
+		{int64(60), "1m0s"},
+		{2 * time.Minute, "2m0s"},
+		{"jio", nil},
+		{true, nil},
+	}
+	for _, c := range cases {
+		ctx := NewContext(c.value)
+		Duration().Validate(ctx)
+		if c.output == nil && ctx.Err == nil || c.output != nil && (ctx.Err != nil || ctx.Value != c.output) {
+			t.Error("should parse the duration", c.value, ctx.Value, ctx.Err)
+		}
+	}
+
+	ctx := NewContext("1m")
+	Duration().Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should no error")
+	}
+	ctx = NewContext("1y")
+	Duration().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "duration.parse" {
+		t.Error("should report the invalid string")
+	}
+	ctx = NewContext(map[string]interface{}{})
+	Duration().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "duration.base" {
+		t.Error("should report the invalid value")
+	}
+}
+
+func TestDurationSchema_Output(t *testing.T) {
+	ctx := NewContext("1m30s")
+	Duration().Output(DurationSeconds).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != 90.0 {
+		t.Error("should output the seconds")
+	}
+	ctx = NewContext("1m30s")
+	Duration().Output(DurationValue).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != 90*time.Second {
+		t.Error("should output the time.Duration")
+	}
+
+	data := []byte(`{"timeout": "1h30m", "interval": 15}`)
+	_, err := ValidateJSON(&data, Object().Keys(K{"timeout": Duration(), "interval": Duration()}))
+	if err != nil || string(data) != `{"interval":"15s","timeout":"1h30m0s"}` {
+		t.Error("should write back the normalized durations", string(data))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("should panic on the unknown output")
+		}
+	}()
+	Duration().Output("minutes")
+}
+
+func TestDurationSchema_Required(t *testing.T) {
+	ctx := NewContext(nil)
+	Duration().Required().Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" {
+		t.Error("should error when no data")
+	}
+	ctx = NewContext(nil)
+	Duration().Optional().Min(time.Second).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != nil {
+		t.Error("should no error")
+	}
+	ctx = NewContext(nil)
+	Duration().Default(time.Minute).Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "1m0s" {
+		t.Error("should set the default value")
+	}
+}
+
+func TestDurationSchema_MinMax(t *testing.T) {
+	schema := Duration().Min(time.Second).Max(time.Hour)
+	for value, rule := range map[string]string{"1s": "", "1h": "", "999ms": "duration.min", "61m": "duration.max"} {
+		ctx := NewContext(value)
+		schema.Validate(ctx)
+		err, _ := ctx.Err.(*ValidationError)
+		if rule == "" && ctx.Err != nil || rule != "" && (err == nil || err.Rule != rule) {
+			t.Error("should check the limits", value)
+		}
+	}
+	ctx := NewContext("2h")
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Args["limit"] != "1h0m0s" {
+		t.Error("should report the limit as a duration")
+	}
+}
+
+func TestDurationSchema_Check(t *testing.T) {
+	schema := Duration().Check(func(value time.Duration) error {
+		if value%time.Second != 0 {
+			return errors.New("must be whole seconds")
+		}
+		return nil
+	})
+	ctx := NewContext("1.5s")
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "duration.check" {
+		t.Error("should check the value")
+	}
+}
+
+func TestDurationSchema_Describe(t *testing.T) {
+	description := Duration().Min(time.Second).Describe()
+	if description.Type != "duration" || description.Rules[0].Name != "parse" || description.Rules[1].Name != "min" {
+		t.Error("should describe the duration")
+	}
+	result := JSONSchema(Duration().Max(time.Hour))
+	if result["maximum"] != nil || len(result["x-jio-rules"].([]string)) != 2 {
+		t.Error("should export the limits as the jio rules")
+	}
+}
+
+func TestDurationSchema_Generate(t *testing.T) {
+	schema := Object().Keys(K{"timeout": Duration().Required().Min(time.Second).Max(time.Minute)})
+	for seed := int64(0); seed < 20; seed++ {
+		if _, err := Generate(schema, rand.NewSource(seed)); err != nil {
+			t.Error("should generate a valid duration", err)
+		}
+	}
+	mutations, err := Mutate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Error("should mutate the duration", err)
+	}
+	rules := map[string]bool{}
+	for _, mutation := range mutations {
+		rules[mutation.Rule] = true
+	}
+	if !rules["duration.base"] || !rules["duration.parse"] || !rules["duration.min"] || !rules["duration.max"] {
+		t.Error("should break every rule of the duration", rules)
+	}
+}
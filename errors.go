@@ -0,0 +1,90 @@
+package jio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a value that failed a rule of the schema.
+// All errors thrown during validation are ValidationError, use errors.As to get it.
+type ValidationError struct {
+	// Path is the field path of the value, joined with `.`.
+	Path string
+	// Rule is the code of the failed rule, such as `string.min`.
+	Rule string
+	// Value is the value that failed the rule.
+	Value interface{}
+	// Args are the arguments of the rule, such as the `limit` of `string.min`.
+	Args map[string]interface{}
+	// Message is the rendered error message.
+	Message string
+
+	err error
+}
+
+// Error returns the rendered message.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error passed to Context.Abort or returned by a Check function, if any.
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+// ValidationErrors lists all errors of the validation when AbortEarly is disabled.
+type ValidationErrors []*ValidationError
+
+// Error joins the messages of all errors.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns all errors, so errors.As can find the first ValidationError.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// renderMessage replace the `{{name}}` placeholders of the template.
+// `label` is the field path and `value` is the value, others come from args.
+func renderMessage(template, label string, value interface{}, args map[string]interface{}) string {
+	if !strings.Contains(template, "{{") {
+		return template
+	}
+	var sb strings.Builder
+	for {
+		start := strings.Index(template, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(template[start:], "}}")
+		if end < 0 {
+			break
+		}
+		sb.WriteString(template[:start])
+		name := strings.TrimSpace(template[start+2 : start+end])
+		switch name {
+		case "label":
+			sb.WriteString(label)
+		case "value":
+			fmt.Fprint(&sb, value)
+		default:
+			if arg, ok := args[name]; ok {
+				fmt.Fprint(&sb, arg)
+			} else {
+				sb.WriteString(template[start : start+end+2])
+			}
+		}
+		template = template[start+end+2:]
+	}
+	sb.WriteString(template)
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package jio
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	data := []byte(`{"window": {"title": "hi"}}`)
+	_, err := ValidateJSON(&data, Object().Keys(K{
+		"window": Object().Keys(K{
+			"title": String().Min(3),
+		}),
+	}))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatal("should be ValidationError")
+	}
+	if validationErr.Path != "window.title" {
+		t.Error("error path")
+	}
+	if validationErr.Rule != "string.min" {
+		t.Error("error rule")
+	}
+	if validationErr.Value != "hi" {
+		t.Error("error value")
+	}
+	if validationErr.Args["limit"] != 3 {
+		t.Error("error args")
+	}
+	if validationErr.Error() != "field `window.title` value hi length less than 3" {
+		t.Error("error message")
+	}
+}
+
+func TestValidationError_Unwrap(t *testing.T) {
+	customErr := errors.New("custom")
+
+	ctx := NewContext("hi")
+	String().Check(func(string) error { return customErr }).Validate(ctx)
+	var validationErr *ValidationError
+	if !errors.As(ctx.Err, &validationErr) || validationErr.Rule != "string.check" {
+		t.Error("should be string.check")
+	}
+	if !errors.Is(ctx.Err, customErr) {
+		t.Error("should unwrap to the check error")
+	}
+
+	ctx = NewContext("hi")
+	ctx.Abort(customErr)
+	if !errors.As(ctx.Err, &validationErr) || validationErr.Rule != "any.custom" {
+		t.Error("should be any.custom")
+	}
+	if ctx.Err.Error() != "custom" || !errors.Is(ctx.Err, customErr) {
+		t.Error("should keep the abort error")
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	message := renderMessage("field `{{label}}` value {{value}} {{ limit }} {{unknown}}", "a.b", 1, map[string]interface{}{"limit": 2})
+	if message != "field `a.b` value 1 2 {{unknown}}" {
+		t.Error("render failed")
+	}
+	if renderMessage("plain", "", nil, nil) != "plain" {
+		t.Error("render plain failed")
+	}
+}
@@ -0,0 +1,249 @@
+package jio
+
+import (
+	"encoding/base64"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// Email check if the value is an email address such as `jio@example.com`, parsed by net/mail.
+// The addresses with a display name such as `Jio <jio@example.com>` are rejected.
+func (s *StringSchema) Email() *StringSchema {
+	return s.check("string.email", nil, func(ctxValue string) bool {
+		address, err := mail.ParseAddress(ctxValue)
+		return err == nil && address.Name == "" && address.Address == ctxValue
+	})
+}
+
+// UUID check if the value is a UUID in the canonical form such as `123e4567-e89b-12d3-a456-426614174000`,
+// the case of the hex digits is ignored.
+// When the versions are provided, the version digit must be one of them and the variant must be RFC 4122,
+// otherwise the version and the variant are not checked.
+func (s *StringSchema) UUID(versions ...int) *StringSchema {
+	for _, version := range versions {
+		if version < 1 || version > 8 {
+			panic("jio: the uuid version must be between 1 and 8")
+		}
+	}
+	return s.check("string.uuid", map[string]interface{}{"versions": versions}, func(ctxValue string) bool {
+		if len(ctxValue) != 36 {
+			return false
+		}
+		for i := 0; i < len(ctxValue); i++ {
+			if i == 8 || i == 13 || i == 18 || i == 23 {
+				if ctxValue[i] != '-' {
+					return false
+				}
+			} else if !isHexDigit(ctxValue[i]) {
+				return false
+			}
+		}
+		if len(versions) == 0 {
+			return true
+		}
+		if !strings.ContainsRune("89abAB", rune(ctxValue[19])) {
+			return false
+		}
+		for _, version := range versions {
+			if int(ctxValue[14]-'0') == version {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// URI check if the value is an absolute URI with a scheme such as `https://example.com/path`, parsed by net/url.
+// When the schemes are provided, the scheme must be one of them, the case is ignored.
+func (s *StringSchema) URI(schemes ...string) *StringSchema {
+	return s.check("string.uri", map[string]interface{}{"schemes": schemes}, func(ctxValue string) bool {
+		// net/url accepts the spaces and the control characters in the paths, which are not allowed by RFC 3986.
+		for i := 0; i < len(ctxValue); i++ {
+			if ctxValue[i] <= ' ' || ctxValue[i] == 0x7f {
+				return false
+			}
+		}
+		u, err := url.Parse(ctxValue)
+		if err != nil || u.Scheme == "" {
+			return false
+		}
+		if len(schemes) == 0 {
+			return true
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Hostname check if the value is a hostname of RFC 1123 such as `api.example.com`,
+// the labels contain a-z, A-Z, 0-9 and hyphens, and don't start or end with a hyphen.
+func (s *StringSchema) Hostname() *StringSchema {
+	return s.check("string.hostname", nil, func(ctxValue string) bool {
+		if ctxValue == "" || len(ctxValue) > 253 {
+			return false
+		}
+		for _, label := range strings.Split(ctxValue, ".") {
+			if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+				return false
+			}
+			for i := 0; i < len(label); i++ {
+				if c := label[i]; c != '-' && !isAlphanumeric(c) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// IP check if the value is an IP address parsed by net.ParseIP, such as `192.168.0.1` or `2001:db8::1`.
+// The versions are 4 and 6, both are accepted when no version is provided.
+// The IPv4-mapped IPv6 addresses such as `::ffff:192.168.0.1` are IPv6 addresses.
+func (s *StringSchema) IP(versions ...int) *StringSchema {
+	checkIPVersions(versions)
+	return s.check("string.ip", map[string]interface{}{"versions": versions}, func(ctxValue string) bool {
+		return ipVersionMatched(net.ParseIP(ctxValue), ctxValue, versions)
+	})
+}
+
+// CIDR check if the value is an IP address with a prefix length parsed by net.ParseCIDR, such as `10.0.0.0/8`,
+// see IP for the versions.
+func (s *StringSchema) CIDR(versions ...int) *StringSchema {
+	checkIPVersions(versions)
+	return s.check("string.cidr", map[string]interface{}{"versions": versions}, func(ctxValue string) bool {
+		ip, _, err := net.ParseCIDR(ctxValue)
+		return err == nil && ipVersionMatched(ip, ctxValue, versions)
+	})
+}
+
+func checkIPVersions(versions []int) {
+	for _, version := range versions {
+		if version != 4 && version != 6 {
+			panic("jio: the ip version must be 4 or 6")
+		}
+	}
+}
+
+// ipVersionMatched check if the parsed ip is one of the versions, the addresses written with colons are IPv6.
+func ipVersionMatched(ip net.IP, value string, versions []int) bool {
+	if ip == nil {
+		return false
+	}
+	if len(versions) == 0 {
+		return true
+	}
+	version := 4
+	if strings.Contains(value, ":") {
+		version = 6
+	}
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Hex check if the value only contains the hex digits 0-9, a-f and A-F.
+func (s *StringSchema) Hex() *StringSchema {
+	return s.check("string.hex", nil, func(ctxValue string) bool {
+		if ctxValue == "" {
+			return false
+		}
+		for i := 0; i < len(ctxValue); i++ {
+			if !isHexDigit(ctxValue[i]) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Base64 check if the value can be decoded by encoding/base64, with the URL-safe alphabet when urlSafe is true.
+// The padding is optional.
+func (s *StringSchema) Base64(urlSafe bool) *StringSchema {
+	encoding, raw := base64.StdEncoding, base64.RawStdEncoding
+	if urlSafe {
+		encoding, raw = base64.URLEncoding, base64.RawURLEncoding
+	}
+	return s.check("string.base64", map[string]interface{}{"urlSafe": urlSafe}, func(ctxValue string) bool {
+		// the decoders skip the newlines.
+		if strings.ContainsAny(ctxValue, "\r\n") {
+			return false
+		}
+		if strings.HasSuffix(ctxValue, "=") {
+			_, err := encoding.DecodeString(ctxValue)
+			return err == nil
+		}
+		_, err := raw.DecodeString(ctxValue)
+		return err == nil
+	})
+}
+
+// Country check if the value is an ISO 3166-1 alpha-2 country code in uppercase, such as `CN` or `US`.
+func (s *StringSchema) Country() *StringSchema {
+	return s.check("string.country", nil, func(ctxValue string) bool {
+		return len(ctxValue) == 2 && strings.Contains(countryCodes, " "+ctxValue+" ")
+	})
+}
+
+// countryCodes are the officially assigned codes of ISO 3166-1 alpha-2, surrounded by spaces.
+const countryCodes = " " +
+	"AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ " +
+	"BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+	"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ " +
+	"DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR " +
+	"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU " +
+	"ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ " +
+	"LA LB LC LI LK LR LS LT LU LV LY " +
+	"MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ " +
+	"NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA " +
+	"RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ " +
+	"TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ " +
+	"VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW "
+
+// stringFormat add the rule of the format name, which is a format of JSON Schema such as `email` and `ipv4`,
+// or the name of a rule above such as `hex`. It returns false when the format is unknown.
+func stringFormat(schema *StringSchema, format string) (*StringSchema, bool) {
+	switch format {
+	case "email":
+		return schema.Email(), true
+	case "uuid":
+		return schema.UUID(), true
+	case "uri":
+		return schema.URI(), true
+	case "hostname":
+		return schema.Hostname(), true
+	case "ip":
+		return schema.IP(), true
+	case "ipv4":
+		return schema.IP(4), true
+	case "ipv6":
+		return schema.IP(6), true
+	case "cidr":
+		return schema.CIDR(), true
+	case "hex":
+		return schema.Hex(), true
+	case "base64":
+		return schema.Base64(false), true
+	case "base64url":
+		return schema.Base64(true), true
+	case "country":
+		return schema.Country(), true
+	}
+	return schema, false
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func isAlphanumeric(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
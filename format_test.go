@@ -0,0 +1,178 @@
+package jio
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStringSchema_Formats(t *testing.T) {
+	cases := []struct {
+		name    string
+		schema  *StringSchema
+		rule    string
+		valid   []string
+		invalid []string
+	}{
+		{"email", String().Email(), "string.email",
+			[]string{"jio@example.com", "first.last+tag@sub.example.org"},
+			[]string{"jio", "jio@", "@example.com", "Jio <jio@example.com>", " jio@example.com", "a b@example.com"}},
+		{"uuid", String().UUID(), "string.uuid",
+			[]string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			[]string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g", "{123e4567-e89b-12d3-a456-426614174000}"}},
+		{"uuid v4", String().UUID(4), "string.uuid",
+			[]string{"f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+			[]string{"123e4567-e89b-12d3-a456-426614174000", "f47ac10b-58cc-4372-c567-0e02b2c3d479"}},
+		{"uri", String().URI(), "string.uri",
+			[]string{"https://example.com/path?q=1#top", "mailto:jio@example.com", "urn:isbn:0451450523"},
+			[]string{"example.com", "/path", "https://exa mple.com", "https://example.com/a b", "://example.com"}},
+		{"uri schemes", String().URI("http", "https"), "string.uri",
+			[]string{"HTTPS://example.com"},
+			[]string{"ftp://example.com"}},
+		{"hostname", String().Hostname(), "string.hostname",
+			[]string{"example.com", "api-1.example.com", "localhost"},
+			[]string{"", "-example.com", "example-.com", "example..com", "exa_mple.com", "example.com."}},
+		{"ip", String().IP(), "string.ip",
+			[]string{"192.168.0.1", "2001:db8::1", "::ffff:192.168.0.1"},
+			[]string{"256.0.0.1", "192.168.0", "2001:db8:::1", "10.0.0.0/8"}},
+		{"ipv4", String().IP(4), "string.ip",
+			[]string{"192.168.0.1"},
+			[]string{"2001:db8::1", "::ffff:192.168.0.1"}},
+		{"ipv6", String().IP(6), "string.ip",
+			[]string{"2001:db8::1", "::ffff:192.168.0.1"},
+			[]string{"192.168.0.1"}},
+		{"cidr", String().CIDR(), "string.cidr",
+			[]string{"10.0.0.0/8", "2001:db8::/32"},
+			[]string{"10.0.0.0", "10.0.0.0/33", "2001:db8::/129"}},
+		{"cidr v4", String().CIDR(4), "string.cidr",
+			[]string{"10.0.0.0/8"},
+			[]string{"2001:db8::/32"}},
+		{"hex", String().Hex(), "string.hex",
+			[]string{"deadBEEF", "0"},
+			[]string{"", "0x1f", "xyz"}},
+		{"base64", String().Base64(false), "string.base64",
+			[]string{"aGVsbG8=", "aGVsbG8", "+/8="},
+			[]string{"aGVsbG8==", "aGV sbG8=", "aGVs\nbG8=", "-_8="}},
+		{"base64 url", String().Base64(true), "string.base64",
+			[]string{"-_8=", "-_8"},
+			[]string{"+/8="}},
+		{"country", String().Country(), "string.country",
+			[]string{"CN", "US", "DE", "ZW"},
+			[]string{"cn", "XX", "USA", ""}},
+	}
+	for _, c := range cases {
+		for _, value := range c.valid {
+			ctx := NewContext(value)
+			c.schema.Validate(ctx)
+			if ctx.Err != nil {
+				t.Error("should accept", c.name, value, ctx.Err)
+			}
+		}
+		for _, value := range c.invalid {
+			ctx := NewContext(value)
+			c.schema.Validate(ctx)
+			if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != c.rule {
+				t.Error("should reject", c.name, value)
+			}
+		}
+	}
+}
+
+func TestStringSchema_FormatVersions(t *testing.T) {
+	for _, f := range []func(){
+		func() { String().UUID(9) },
+		func() { String().IP(5) },
+		func() { String().CIDR(0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("should panic on the unknown version")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestStringSchema_FormatJSONSchema(t *testing.T) {
+	result := JSONSchema(String().Email())
+	if result["format"] != "email" {
+		t.Error("should export the format")
+	}
+	result = JSONSchema(String().IP(6).Base64(false))
+	if result["format"] != "ipv6" || result["contentEncoding"] != "base64" {
+		t.Error("should export the ip version and the base64 encoding")
+	}
+	result = JSONSchema(String().IP().Country())
+	if result["format"] != nil || len(result["x-jio-rules"].([]string)) != 2 {
+		t.Error("should list the formats without a keyword as the jio rules")
+	}
+
+	schema, err := FromJSONSchema(map[string]interface{}{"type": "string", "format": "uuid"})
+	if err != nil {
+		t.Error("should import the format", err)
+	}
+	ctx := NewContext("jio")
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "string.uuid" {
+		t.Error("should validate the imported format")
+	}
+	schema, err = FromJSONSchema(map[string]interface{}{"type": "string", "format": "iri-reference"})
+	if err != nil {
+		t.Error("should ignore the unknown format", err)
+	}
+	ctx = NewContext("jio")
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should not validate the unknown format")
+	}
+}
+
+func TestLoader_Format(t *testing.T) {
+	schema, err := NewLoader().Load([]byte(`{"type": "string", "trim": true, "format": "ipv4"}`))
+	if err != nil {
+		t.Error("should load the format", err)
+	}
+	ctx := NewContext(" 10.0.0.1 ")
+	schema.Validate(ctx)
+	if ctx.Err != nil || ctx.Value != "10.0.0.1" {
+		t.Error("should validate the loaded format")
+	}
+	_, err = NewLoader().Load([]byte(`{"type": "string", "format": "isbn"}`))
+	if err == nil || err.Error() != `jio: #/format (line 1, column 30): unknown format "isbn"` {
+		t.Error("should report the unknown format", err)
+	}
+}
+
+func TestStringSchema_FormatGenerate(t *testing.T) {
+	schema := Object().Keys(K{
+		"email":   String().Required().Email(),
+		"id":      String().Required().UUID(7),
+		"site":    String().Required().URI("https"),
+		"host":    String().Required().Hostname(),
+		"ip":      String().Required().IP(6),
+		"network": String().Required().CIDR(),
+		"color":   String().Required().Hex(),
+		"token":   String().Required().Base64(true),
+		"country": String().Required().Country(),
+	})
+	for seed := int64(0); seed < 20; seed++ {
+		if _, err := Generate(schema, rand.NewSource(seed)); err != nil {
+			t.Error("should generate the formats", err)
+		}
+	}
+	mutations, err := Mutate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Error("should mutate the formats", err)
+	}
+	rules := map[string]bool{}
+	for _, mutation := range mutations {
+		rules[mutation.Rule] = true
+	}
+	for _, rule := range []string{"string.email", "string.uuid", "string.uri", "string.hostname", "string.ip",
+		"string.cidr", "string.hex", "string.base64", "string.country"} {
+		if !rules[rule] {
+			t.Error("should break the format", rule)
+		}
+	}
+}
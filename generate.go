@@ -0,0 +1,804 @@
+package jio
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+)
+
+// generateAttempts is the number of values tried for a schema before Generate gives up.
+const generateAttempts = 50
+
+// generateMaxDepth stops generating the optional keys and the array items of the deeply nested schemas.
+const generateMaxDepth = 16
+
+var errGenerate = errors.New("jio: cannot generate a valid value for the schema")
+
+// Generate returns a random example value which passes the validation of the schema, for fixtures and property tests.
+// It honors the required keys, Equal, Valid, Min, Max, Length, Greater, Less, Integer, Regex, the formats such as Email,
+// Keys, With, Items and the limits of the dates, the durations and the sizes,
+// the regex is generated from the literals, the classes, the alternations and the repeats of the pattern.
+// Every value is checked by the schema and generated again when it fails a rule that can't be honored,
+// such as a Check or a When, and an error is returned when none of the attempts is valid.
+// The same source always generates the same value, except for the dates limited relative to the current time,
+// such as Date().Min(-time.Hour), whose range moves with time.Now().
+func Generate(schema Schema, source rand.Source) (interface{}, error) {
+	g := &generator{rand: rand.New(source)}
+	return g.valid(schema, 0)
+}
+
+// Mutation is a minimally-invalid value which fails a single rule of the schema.
+type Mutation struct {
+	// Path is the field path of the failed value, empty for the root.
+	Path string
+	// Rule is the code of the failed rule, such as `string.max`.
+	Rule string
+	// Value is the whole value, which is the same as the valid value except at the path.
+	Value interface{}
+}
+
+// Mutate generates a valid value like Generate, then breaks the rules one by one,
+// such as removing a required key or exceeding a Max, and returns the mutations failing exactly one rule,
+// at most one mutation for each path and rule.
+func Mutate(schema Schema, source rand.Source) ([]Mutation, error) {
+	g := &generator{rand: rand.New(source)}
+	value, err := g.valid(schema, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var mutations []Mutation
+	seen := map[string]bool{}
+	g.mutations(schema, value, nil, func(path []interface{}, mutated interface{}) {
+		root := replaceValue(value, path, mutated)
+		ctx := NewContext(cloneValue(root), AbortEarly(false))
+		schema.Validate(ctx)
+		if len(ctx.errors) != 1 {
+			return
+		}
+		err := ctx.errors[0]
+		if key := err.Path + "\x00" + err.Rule; !seen[key] {
+			seen[key] = true
+			mutations = append(mutations, Mutation{Path: err.Path, Rule: err.Rule, Value: root})
+		}
+	})
+	return mutations, nil
+}
+
+type generator struct {
+	rand *rand.Rand
+}
+
+// generateSpec collects the rules of a schema from its descs.
+type generateSpec struct {
+	typ      string
+	valids   []interface{}
+	hasValid bool
+	min, max *float64
+	greater  *float64
+	less     *float64
+	length   *float64
+	integer  bool
+	regexes  []string
+	formats  []ruleDesc
+	keys     K
+	with     []string
+	// discriminator is the key selecting the cases of Discriminator.
+	discriminator string
+	cases         map[string]Schema
+	items         []Schema
+	schemas       []Schema
+	allOf         bool
+	// dateMin and dateMax are the inclusive limits of the dates, the referenced limits are left to the validation.
+	dateMin, dateMax *time.Time
+}
+
+func newGenerateSpec(schema Schema) (*generateSpec, bool) {
+	if typed, ok := schema.(interface{ Schema() Schema }); ok {
+		schema = typed.Schema()
+	}
+	for {
+		link, ok := schema.(*LinkSchema)
+		if !ok {
+			break
+		}
+		schema = link.resolve()
+	}
+	spec := &generateSpec{}
+	switch schema.(type) {
+	case *AnySchema:
+		spec.typ = "any"
+	case *StringSchema:
+		spec.typ = "string"
+	case *NumberSchema:
+		spec.typ = "number"
+	case *BoolSchema:
+		spec.typ = "bool"
+	case *ArraySchema:
+		spec.typ = "array"
+	case *ObjectSchema:
+		spec.typ = "object"
+	case *AlternativesSchema:
+		spec.typ = "alternatives"
+	case *DateSchema:
+		spec.typ = "date"
+	case *DurationSchema:
+		spec.typ = "duration"
+	case *ByteSizeSchema:
+		spec.typ = "byteSize"
+	default:
+		return nil, false
+	}
+
+	limit := func(desc ruleDesc) *float64 {
+		if duration, ok := desc.args["limit"].(time.Duration); ok {
+			value := float64(duration)
+			return &value
+		}
+		value, _ := jsonSchemaNumber(desc.args["limit"])
+		return &value
+	}
+	for _, desc := range schema.(interface{ base() *baseSchema }).base().descs {
+		if spec.typ == "date" {
+			spec.dateLimit(desc)
+			continue
+		}
+		switch desc.name {
+		case "equal":
+			spec.valids, spec.hasValid = []interface{}{desc.args["expected"]}, true
+		case "valid":
+			spec.valids, spec.hasValid = toInterfaces(desc.args["valids"]), true
+		case "min":
+			spec.min = limit(desc)
+		case "max":
+			spec.max = limit(desc)
+		case "length":
+			spec.length = limit(desc)
+		case "greater":
+			spec.greater = limit(desc)
+		case "less":
+			spec.less = limit(desc)
+		case "integer":
+			spec.integer = true
+		case "regex", "alphanum", "token":
+			spec.regexes = append(spec.regexes, desc.args["regex"].(string))
+		case "email", "uuid", "uri", "hostname", "ip", "cidr", "hex", "base64", "country":
+			spec.formats = append(spec.formats, desc)
+		case "keys":
+			if spec.keys == nil {
+				spec.keys = K{}
+			}
+			for key, child := range desc.args["keys"].(K) {
+				spec.keys[key] = child
+			}
+		case "with":
+			spec.with = append(spec.with, desc.args["keys"].([]string)...)
+		case "discriminator":
+			spec.discriminator, spec.cases = desc.args["key"].(string), desc.args["cases"].(map[string]Schema)
+		case "items":
+			spec.items = append(spec.items, desc.args["schemas"].([]Schema)...)
+		case "try", "oneOf", "allOf":
+			spec.schemas, spec.allOf = desc.args["schemas"].([]Schema), desc.name == "allOf"
+		}
+	}
+	return spec, true
+}
+
+// dateLimit narrows the limits of the dates with the desc of Min, Max, Before or After.
+func (spec *generateSpec) dateLimit(desc ruleDesc) {
+	var limit time.Time
+	switch v := desc.args["limit"].(type) {
+	case time.Time:
+		limit = v
+	case time.Duration:
+		limit = time.Now().Add(v)
+	default:
+		return
+	}
+	switch desc.name {
+	case "after":
+		limit = limit.Add(time.Second)
+		fallthrough
+	case "min":
+		if spec.dateMin == nil || limit.After(*spec.dateMin) {
+			spec.dateMin = &limit
+		}
+	case "before":
+		limit = limit.Add(-time.Second)
+		fallthrough
+	case "max":
+		if spec.dateMax == nil || limit.Before(*spec.dateMax) {
+			spec.dateMax = &limit
+		}
+	}
+}
+
+// valid generates the values of the schema until one passes the validation.
+func (g *generator) valid(schema Schema, depth int) (interface{}, error) {
+	spec, ok := newGenerateSpec(schema)
+	if !ok {
+		return nil, fmt.Errorf("jio: cannot generate a value of the schema %T", schema)
+	}
+	for i := 0; i < generateAttempts; i++ {
+		value, ok := g.value(spec, depth)
+		if !ok {
+			continue
+		}
+		ctx := NewContext(cloneValue(value))
+		schema.Validate(ctx)
+		if ctx.Err == nil {
+			return value, nil
+		}
+	}
+	return nil, errGenerate
+}
+
+func (g *generator) value(spec *generateSpec, depth int) (interface{}, bool) {
+	if spec.hasValid {
+		if len(spec.valids) == 0 {
+			return nil, false
+		}
+		return spec.valids[g.rand.Intn(len(spec.valids))], true
+	}
+	switch spec.typ {
+	case "string":
+		return g.string(spec)
+	case "number":
+		return g.number(spec)
+	case "bool":
+		return g.rand.Intn(2) == 0, true
+	case "array":
+		return g.array(spec, depth)
+	case "object":
+		return g.object(spec, depth)
+	case "date":
+		return g.date(spec)
+	case "duration", "byteSize":
+		return g.quantity(spec)
+	}
+	if len(spec.schemas) > 0 {
+		schema := spec.schemas[0]
+		if !spec.allOf {
+			schema = spec.schemas[g.rand.Intn(len(spec.schemas))]
+		}
+		value, err := g.valid(schema, depth+1)
+		return value, err == nil
+	}
+	return g.letters(1 + g.rand.Intn(8)), true
+}
+
+// size returns a random size between the min and the max, the max is the min plus extra when it's not set.
+func (g *generator) size(spec *generateSpec, extra int) (int, bool) {
+	if spec.length != nil {
+		return int(*spec.length), true
+	}
+	min, max := 0, -1
+	if spec.min != nil {
+		min = int(*spec.min)
+	}
+	if spec.max != nil {
+		max = int(*spec.max)
+	} else {
+		max = min + extra
+	}
+	if min > max {
+		return 0, false
+	}
+	return min + g.rand.Intn(max-min+1), true
+}
+
+func (g *generator) string(spec *generateSpec) (interface{}, bool) {
+	if len(spec.formats) > 0 {
+		return g.format(spec.formats[0]), true
+	}
+	if len(spec.regexes) > 0 {
+		return g.regex(spec.regexes[g.rand.Intn(len(spec.regexes))])
+	}
+	size, ok := g.size(spec, 10)
+	if !ok {
+		return nil, false
+	}
+	return g.letters(size), true
+}
+
+// format generates a string of the format rule.
+func (g *generator) format(desc ruleDesc) string {
+	switch desc.name {
+	case "email":
+		return g.letters(1+g.rand.Intn(8)) + "@example.com"
+	case "uuid":
+		digits := []byte(fmt.Sprintf("%016x%016x", g.rand.Uint64(), g.rand.Uint64()))
+		version := 4
+		if versions, _ := desc.args["versions"].([]int); len(versions) > 0 {
+			version = versions[g.rand.Intn(len(versions))]
+		}
+		digits[12] = byte('0' + version)
+		digits[16] = "89ab"[g.rand.Intn(4)]
+		return fmt.Sprintf("%s-%s-%s-%s-%s", digits[:8], digits[8:12], digits[12:16], digits[16:20], digits[20:])
+	case "uri":
+		scheme := "https"
+		if schemes, _ := desc.args["schemes"].([]string); len(schemes) > 0 {
+			scheme = schemes[g.rand.Intn(len(schemes))]
+		}
+		return scheme + "://example.com/" + g.letters(g.rand.Intn(8))
+	case "hostname":
+		return g.letters(1+g.rand.Intn(8)) + ".example.com"
+	case "ip", "cidr":
+		version := 4
+		if versions, _ := desc.args["versions"].([]int); len(versions) > 0 {
+			version = versions[g.rand.Intn(len(versions))]
+		}
+		if version == 6 {
+			if desc.name == "cidr" {
+				return fmt.Sprintf("2001:db8:%x::/48", g.rand.Intn(1<<16))
+			}
+			return fmt.Sprintf("2001:db8::%x", g.rand.Intn(1<<16))
+		}
+		ip := fmt.Sprintf("10.%d.%d.%d", g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256))
+		if desc.name == "cidr" {
+			return ip + "/32"
+		}
+		return ip
+	case "hex":
+		return fmt.Sprintf("%x", g.rand.Uint64())
+	case "base64":
+		data := make([]byte, 1+g.rand.Intn(12))
+		g.rand.Read(data)
+		if desc.args["urlSafe"] == true {
+			return base64.URLEncoding.EncodeToString(data)
+		}
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	codes := strings.Fields(countryCodes)
+	return codes[g.rand.Intn(len(codes))]
+}
+
+const generateLetters = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+func (g *generator) letters(size int) string {
+	var sb strings.Builder
+	for i := 0; i < size; i++ {
+		sb.WriteByte(generateLetters[g.rand.Intn(len(generateLetters))])
+	}
+	return sb.String()
+}
+
+// regex generates a string matching the simple subset of the pattern, the repeats are limited to a few times.
+func (g *generator) regex(pattern string) (interface{}, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+	var sb strings.Builder
+	if !g.regexp(&sb, re.Simplify()) {
+		return nil, false
+	}
+	return sb.String(), true
+}
+
+func (g *generator) regexp(sb *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return false
+		}
+		i := g.rand.Intn(len(re.Rune)/2) * 2
+		lo, hi := re.Rune[i], re.Rune[i+1]
+		// prefer the printable ascii characters in the wide ranges of the negated classes
+		if lo <= '~' && hi >= ' ' {
+			lo, hi = maxRune(lo, ' '), minRune(hi, '~')
+		}
+		sb.WriteRune(lo + rune(g.rand.Intn(int(hi-lo)+1)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(generateLetters[g.rand.Intn(len(generateLetters))])
+	case syntax.OpCapture:
+		return g.regexp(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.regexp(sb, sub) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return g.regexp(sb, re.Sub[g.rand.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := 0, 3
+		switch re.Op {
+		case syntax.OpPlus:
+			min, max = 1, 4
+		case syntax.OpQuest:
+			max = 1
+		case syntax.OpRepeat:
+			min, max = re.Min, re.Max
+			if max < 0 {
+				max = min + 3
+			}
+		}
+		for n := min + g.rand.Intn(max-min+1); n > 0; n-- {
+			if !g.regexp(sb, re.Sub[0]) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func minRune(a, b rune) rune {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxRune(a, b rune) rune {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (g *generator) number(spec *generateSpec) (interface{}, bool) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if spec.min != nil {
+		lo = *spec.min
+	}
+	if spec.greater != nil && *spec.greater >= lo {
+		lo = *spec.greater
+	}
+	if spec.max != nil {
+		hi = *spec.max
+	}
+	if spec.less != nil && *spec.less <= hi {
+		hi = *spec.less
+	}
+	switch {
+	case math.IsInf(lo, -1) && math.IsInf(hi, 1):
+		lo, hi = 0, 100
+	case math.IsInf(lo, -1):
+		lo = hi - 100
+	case math.IsInf(hi, 1):
+		hi = lo + 100
+	}
+	if spec.integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if lo > hi {
+			return nil, false
+		}
+		if hi-lo > 1e9 {
+			hi = lo + 1e9
+		}
+		return lo + float64(g.rand.Int63n(int64(hi-lo)+1)), true
+	}
+	if lo > hi {
+		return nil, false
+	}
+	value := math.Round((lo+g.rand.Float64()*(hi-lo))*100) / 100
+	return math.Max(lo, math.Min(hi, value)), true
+}
+
+// dateSpan is the span of the generated dates when a limit is absent, about ten years.
+const dateSpan = 10 * 365 * 24 * time.Hour
+
+// date generates a RFC 3339 string in whole seconds, the dates start from the year 2000 without the limits.
+func (g *generator) date(spec *generateSpec) (interface{}, bool) {
+	var lo, hi time.Time
+	switch {
+	case spec.dateMin != nil && spec.dateMax != nil:
+		lo, hi = *spec.dateMin, *spec.dateMax
+	case spec.dateMin != nil:
+		lo = *spec.dateMin
+		hi = lo.Add(dateSpan)
+	case spec.dateMax != nil:
+		hi = *spec.dateMax
+		lo = hi.Add(-dateSpan)
+	default:
+		lo = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		hi = lo.Add(dateSpan)
+	}
+	if lo.Truncate(time.Second).Before(lo) {
+		lo = lo.Truncate(time.Second).Add(time.Second)
+	}
+	seconds := hi.Unix() - lo.Unix()
+	if seconds < 0 {
+		return nil, false
+	}
+	return time.Unix(lo.Unix()+g.rand.Int63n(seconds+1), 0).UTC().Format(time.RFC3339), true
+}
+
+// quantity generates a duration string in whole seconds or a size string of bytes between the min and the max,
+// the max is a day or a GiB after the min when it's not set.
+func (g *generator) quantity(spec *generateSpec) (interface{}, bool) {
+	step, span := int64(time.Second), int64(24*time.Hour)
+	if spec.typ == "byteSize" {
+		step, span = 1, 1<<30
+	}
+	var lo, hi int64
+	if spec.min != nil {
+		lo = int64(math.Ceil(*spec.min/float64(step))) * step
+	}
+	if spec.max != nil {
+		hi = int64(math.Floor(*spec.max/float64(step))) * step
+	} else {
+		hi = lo + span
+	}
+	if lo > hi {
+		return nil, false
+	}
+	value := lo + g.rand.Int63n((hi-lo)/step+1)*step
+	if spec.typ == "byteSize" {
+		return formatByteSize(value), true
+	}
+	return time.Duration(value).String(), true
+}
+
+func (g *generator) array(spec *generateSpec, depth int) (interface{}, bool) {
+	if depth >= generateMaxDepth && spec.length == nil {
+		spec = &generateSpec{min: spec.min, max: spec.min, items: spec.items}
+	}
+	size, ok := g.size(spec, 3)
+	if !ok {
+		return nil, false
+	}
+	value := make([]interface{}, size)
+	for i := range value {
+		if len(spec.items) == 0 {
+			value[i] = g.letters(1 + g.rand.Intn(8))
+			continue
+		}
+		item, err := g.valid(spec.items[g.rand.Intn(len(spec.items))], depth+1)
+		if err != nil {
+			return nil, false
+		}
+		value[i] = item
+	}
+	return value, true
+}
+
+func (g *generator) object(spec *generateSpec, depth int) (interface{}, bool) {
+	keys := make([]string, 0, len(spec.keys))
+	for key := range spec.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	value := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		child := spec.keys[key]
+		needed := isRequired(child) || containsString(spec.with, key)
+		if !needed && (depth >= generateMaxDepth || g.rand.Intn(2) == 0) {
+			continue
+		}
+		item, err := g.valid(child, depth+1)
+		if err != nil {
+			if needed {
+				return nil, false
+			}
+			continue
+		}
+		if item != nil || needed {
+			value[key] = item
+		}
+	}
+	if len(spec.cases) > 0 {
+		names := make([]string, 0, len(spec.cases))
+		for name := range spec.cases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		item, err := g.valid(spec.cases[names[g.rand.Intn(len(names))]], depth+1)
+		if err != nil {
+			return nil, false
+		}
+		for key, child := range item.(map[string]interface{}) {
+			value[key] = child
+		}
+	}
+	for _, key := range spec.with {
+		if _, ok := value[key]; !ok {
+			value[key] = g.letters(1 + g.rand.Intn(8))
+		}
+	}
+	return value, true
+}
+
+// removedValue marks the key removed by a mutation.
+type removedValue struct{}
+
+// mutations calls add with the path and the mutated value of every rule of the schema.
+func (g *generator) mutations(schema Schema, value interface{}, path []interface{}, add func([]interface{}, interface{})) {
+	spec, ok := newGenerateSpec(schema)
+	if !ok {
+		return
+	}
+	switch spec.typ {
+	case "string", "bool", "array", "object":
+		add(path, 1.0)
+	case "number":
+		add(path, "jio")
+	case "date":
+		add(path, "jio")
+		if spec.dateMin != nil {
+			add(path, spec.dateMin.Add(-time.Second).UTC().Format(time.RFC3339Nano))
+		}
+		if spec.dateMax != nil {
+			add(path, spec.dateMax.Add(time.Second).UTC().Format(time.RFC3339Nano))
+		}
+		return
+	case "duration", "byteSize":
+		add(path, true)
+		add(path, "jio")
+		format := func(value float64) interface{} { return time.Duration(value).String() }
+		if spec.typ == "byteSize" {
+			format = func(value float64) interface{} { return formatByteSize(int64(value)) }
+		}
+		if spec.min != nil && (spec.typ == "duration" || *spec.min > 0) {
+			add(path, format(*spec.min-1))
+		}
+		if spec.max != nil {
+			add(path, format(*spec.max+1))
+		}
+		return
+	}
+	if spec.hasValid {
+		g.invalidValues(spec, value, path, add)
+	}
+
+	switch v := value.(type) {
+	case string:
+		runes := []rune(v)
+		if spec.min != nil && *spec.min > 0 && len(runes) >= int(*spec.min) {
+			add(path, string(runes[:int(*spec.min)-1]))
+		}
+		if spec.max != nil {
+			add(path, v+strings.Repeat(lastLetter(v), int(*spec.max)+1-len(runes)))
+		}
+		if spec.length != nil {
+			add(path, v+lastLetter(v))
+		}
+		if len(spec.formats) > 0 {
+			add(path, v+" ")
+		}
+		if len(spec.regexes) > 0 {
+			add(path, v+"!")
+			add(path, "!"+v[len(v)/2:])
+		}
+	case float64:
+		if spec.min != nil {
+			add(path, *spec.min-1)
+		}
+		if spec.greater != nil {
+			add(path, *spec.greater)
+		}
+		if spec.max != nil {
+			add(path, *spec.max+1)
+		}
+		if spec.less != nil {
+			add(path, *spec.less)
+		}
+		if spec.integer {
+			add(path, v+0.5)
+			add(path, v-0.5)
+		}
+	case []interface{}:
+		if spec.min != nil && *spec.min > 0 && len(v) >= int(*spec.min) {
+			add(path, append([]interface{}{}, v[:int(*spec.min)-1]...))
+		}
+		if len(v) > 0 && (spec.max != nil || spec.length != nil) {
+			limit := len(v)
+			if spec.max != nil {
+				limit = int(*spec.max)
+			}
+			items := append([]interface{}{}, v...)
+			for len(items) <= limit {
+				items = append(items, cloneValue(v[0]))
+			}
+			add(path, items)
+		}
+		for i, item := range v {
+			for _, itemSchema := range spec.items {
+				ctx := NewContext(cloneValue(item))
+				itemSchema.Validate(ctx)
+				if ctx.Err == nil {
+					g.mutations(itemSchema, item, appendPath(path, i), add)
+					break
+				}
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if name, ok := v[spec.discriminator].(string); ok && spec.cases[name] != nil {
+			add(appendPath(path, spec.discriminator), name+"_"+g.letters(3))
+			g.mutations(spec.cases[name], v, path, add)
+		}
+		for _, key := range keys {
+			if child, ok := spec.keys[key]; ok {
+				if isRequired(child) || containsString(spec.with, key) {
+					add(appendPath(path, key), removedValue{})
+				}
+				g.mutations(child, v[key], appendPath(path, key), add)
+			} else if containsString(spec.with, key) {
+				add(appendPath(path, key), removedValue{})
+			}
+		}
+	}
+}
+
+// invalidValues add the values not in the valid values of the spec.
+func (g *generator) invalidValues(spec *generateSpec, value interface{}, path []interface{}, add func([]interface{}, interface{})) {
+	switch v := value.(type) {
+	case string:
+		add(path, v+"_"+g.letters(3))
+	case float64:
+		max := v
+		for _, valid := range spec.valids {
+			if f, ok := valid.(float64); ok && f > max {
+				max = f
+			}
+		}
+		add(path, math.Floor(max)+1)
+	case bool:
+		add(path, !v)
+	default:
+		add(path, g.letters(8))
+	}
+}
+
+func lastLetter(value string) string {
+	if value == "" {
+		return "a"
+	}
+	runes := []rune(value)
+	return string(runes[len(runes)-1])
+}
+
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	return append(path[:len(path):len(path)], key)
+}
+
+// replaceValue returns a deep copy of the root with the value at the path replaced.
+func replaceValue(root interface{}, path []interface{}, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	root = cloneValue(root)
+	parent := root
+	for i, key := range path {
+		last := i == len(path)-1
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			name := key.(string)
+			if !last {
+				parent = p[name]
+			} else if _, ok := value.(removedValue); ok {
+				delete(p, name)
+			} else {
+				p[name] = value
+			}
+		case []interface{}:
+			index := key.(int)
+			if !last {
+				parent = p[index]
+			} else {
+				p[index] = value
+			}
+		}
+	}
+	return root
+}
@@ -0,0 +1,116 @@
+package jio
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func generateTestSchema() Schema {
+	return Object().Keys(K{
+		"name":  String().Required().Min(3).Max(8),
+		"code":  String().Required().Regex(`^[A-Z]{2}-\d{3}(x|y)?$`),
+		"role":  String().Valid("admin", "guest"),
+		"age":   Number().Required().Integer().Min(18).Less(60),
+		"score": Number().Greater(0).Max(1),
+		"tags":  Array().Required().Min(1).Max(3).Items(String().Required().Token()),
+		"admin": Bool().Required(),
+	}).With("role")
+}
+
+func TestGenerate(t *testing.T) {
+	schema := generateTestSchema()
+	for seed := int64(0); seed < 50; seed++ {
+		value, err := Generate(schema, rand.NewSource(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := NewContext(value)
+		schema.Validate(ctx)
+		if ctx.Err != nil {
+			t.Fatal(ctx.Err)
+		}
+		data := value.(map[string]interface{})
+		if _, ok := data["role"]; !ok {
+			t.Error("should generate the keys of With")
+		}
+	}
+
+	first, _ := Generate(schema, rand.NewSource(1))
+	second, _ := Generate(schema, rand.NewSource(1))
+	if !reflect.DeepEqual(first, second) {
+		t.Error("should generate the same value with the same source")
+	}
+}
+
+func TestGenerate_Check(t *testing.T) {
+	schema := Number().Integer().Min(0).Max(100).Check(func(value float64) error {
+		if int(value)%2 != 0 {
+			return errGenerate
+		}
+		return nil
+	})
+	value, err := Generate(schema, rand.NewSource(1))
+	if err != nil || int(value.(float64))%2 != 0 {
+		t.Error("should retry the values failing the opaque rules")
+	}
+
+	_, err = Generate(String().Min(5).Max(3), rand.NewSource(1))
+	if err != errGenerate {
+		t.Error("should return an error when no valid value can be generated")
+	}
+}
+
+func TestMutate(t *testing.T) {
+	schema := generateTestSchema()
+	mutations, err := Mutate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := map[string]bool{}
+	for _, mutation := range mutations {
+		rules[mutation.Path+" "+mutation.Rule] = true
+		ctx := NewContext(mutation.Value, AbortEarly(false))
+		schema.Validate(ctx)
+		if errs, ok := ctx.Err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Rule != mutation.Rule {
+			t.Errorf("mutation %s %s should fail exactly one rule", mutation.Path, mutation.Rule)
+		}
+	}
+	for _, rule := range []string{
+		"name any.required", "name string.base", "name string.min", "name string.max",
+		"code string.regex", "role string.valid", " object.with",
+		"age number.min", "age number.less", "age number.integer",
+		"tags array.min", "tags array.max", "tags.0 string.token", "admin bool.base",
+	} {
+		if !rules[rule] {
+			t.Errorf("should generate the mutation %s", rule)
+		}
+	}
+}
+
+func TestGenerate_Discriminator(t *testing.T) {
+	schema := Object().Discriminator("type", map[string]K{
+		"click":  {"x": Number().Required()},
+		"scroll": {"offset": Number().Required().Min(0)},
+	})
+	for seed := int64(0); seed < 10; seed++ {
+		value, err := Generate(schema, rand.NewSource(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kind := value.(map[string]interface{})["type"]; kind != "click" && kind != "scroll" {
+			t.Error("should generate the discriminator")
+		}
+	}
+	mutations, err := Mutate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, mutation := range mutations {
+		found = found || mutation.Rule == "object.discriminator"
+	}
+	if !found {
+		t.Error("should mutate the discriminator")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -19,17 +21,94 @@ const (
 )
 
 // ValidateJSON validate the provided json bytes using the schema.
-func ValidateJSON(dataRaw *[]byte, schema Schema) (dataMap map[string]interface{}, err error) {
-	if err = json.Unmarshal(*dataRaw, &dataMap); err != nil {
+// The options such as AbortEarly can be used to configure the validation.
+func ValidateJSON(dataRaw *[]byte, schema Schema, opts ...Option) (dataMap map[string]interface{}, err error) {
+	value, err := validateJSON(dataRaw, false, newOptions(opts).useNumber, func(data interface{}) (interface{}, error) {
+		ctx := NewContext(data, opts...)
+		schema.Validate(ctx)
+		return ctx.Value, ctx.Err
+	})
+	dataMap, _ = value.(map[string]interface{})
+	return dataMap, err
+}
+
+// ValidateJSONValue same as ValidateJSON, but accept any json root such as an array or a string,
+// and returns the validated value.
+func ValidateJSONValue(dataRaw *[]byte, schema Schema, opts ...Option) (interface{}, error) {
+	return validateJSON(dataRaw, true, newOptions(opts).useNumber, func(data interface{}) (interface{}, error) {
+		ctx := NewContext(data, opts...)
+		schema.Validate(ctx)
+		return ctx.Value, ctx.Err
+	})
+}
+
+// ValidateJSONInto same as ValidateJSONValue, and decode the validated value into dst which must be a pointer,
+// so that the defaults and transforms of the schema are applied to the typed Go value.
+// If the validated value cannot be assigned to dst, a ValidationError of the `any.decode` rule is returned.
+func ValidateJSONInto(dataRaw *[]byte, schema Schema, dst interface{}, opts ...Option) error {
+	value, err := ValidateJSONValue(dataRaw, schema, opts...)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(*dataRaw, value, dst, newOptions(opts))
+}
+
+// decodeJSON decode the validated json bytes into dst.
+// The type errors are reported as ValidationError of the `any.decode` rule at the path of the mismatched value.
+func decodeJSON(data []byte, value, dst interface{}, o options) error {
+	err := unmarshalJSON(data, o.useNumber, dst)
+	typeErr, ok := err.(*json.UnmarshalTypeError)
+	if !ok {
+		return err
+	}
+	ctx := &Context{root: value, Value: value, options: o}
+	if typeErr.Field != "" {
+		ctx.fields = strings.Split(typeErr.Field, ".")
+		ctx.Value = lookupValue(value, ctx.fields)
+	}
+	validationErr := ctx.newError("any.decode", map[string]interface{}{"type": typeErr.Type.String()})
+	validationErr.err = typeErr
+	ctx.addError(validationErr)
+	return ctx.Err
+}
+
+// lookupValue returns the value at the path of the object keys and array indexes, or nil if not found.
+func lookupValue(value interface{}, fields []string) interface{} {
+	for _, field := range fields {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[field]
+		case []interface{}:
+			index, err := strconv.Atoi(field)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil
+			}
+			value = v[index]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// validateJSON decode the json bytes, validate it with the function and write back the transformed value.
+// The json root must be an object unless anyRoot is true, and the numbers are decoded into json.Number if useNumber is true.
+func validateJSON(dataRaw *[]byte, anyRoot, useNumber bool, validate func(interface{}) (interface{}, error)) (value interface{}, err error) {
+	if anyRoot {
+		err = unmarshalJSON(*dataRaw, useNumber, &value)
+	} else {
+		var dataMap map[string]interface{}
+		err = unmarshalJSON(*dataRaw, useNumber, &dataMap)
+		value = dataMap
+	}
+	if err != nil {
 		return
 	}
-	ctx := NewContext(dataMap)
-	schema.Validate(ctx)
-	if ctx.Err != nil {
-		return dataMap, ctx.Err
+	value, err = validate(value)
+	if err != nil {
+		return
 	}
-	dataMap = ctx.Value.(map[string]interface{})
-	dataNew, err := json.Marshal(ctx.Value)
+	dataNew, err := json.Marshal(value)
 	if err != nil {
 		return
 	}
@@ -37,6 +116,23 @@ func ValidateJSON(dataRaw *[]byte, schema Schema) (dataMap map[string]interface{
 	return
 }
 
+// unmarshalJSON same as json.Unmarshal, but decode the numbers into json.Number if useNumber is true.
+func unmarshalJSON(data []byte, useNumber bool, v interface{}) error {
+	if !useNumber {
+		return json.Unmarshal(data, v)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data[decoder.InputOffset():])) > 0 {
+		// returns the same error as json.Unmarshal for the trailing data
+		return json.Unmarshal(data, v)
+	}
+	return nil
+}
+
 // DefaultErrorHandler handle and respond the error
 func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
 	code := http.StatusBadRequest
@@ -50,46 +146,118 @@ func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
 
 // ValidateBody validate the request's body using the schema.
 // If the verification fails, the errorHandler will be used to handle the error.
-func ValidateBody(schema Schema, errorHandler func(http.ResponseWriter, *http.Request, error)) func(next http.Handler) http.Handler {
+// The options are applied to every validation, same as ValidateJSON.
+// The schema is recorded to the OpenAPI document if the Route option of OpenAPI is used.
+// The body must be a json object unless the AnyRoot option is used,
+// then the validated value of any type is saved to the request's context.
+func ValidateBody(schema Schema, errorHandler func(http.ResponseWriter, *http.Request, error), opts ...Option) func(next http.Handler) http.Handler {
+	o := newOptions(opts)
+	acceptLanguage, anyRoot := o.acceptLanguage, o.anyRoot
+	o.route.register("body", schema)
+	validator := Compile(schema, opts...)
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			var body []byte
-			var err error
-			if strings.Contains(r.Header.Get("Content-type"), "application/json") {
-				body, err = ioutil.ReadAll(r.Body)
-				if err != nil {
-					return
-				}
-				r.Body.Close()
+			body, err := readJSONBody(r)
+			if err != nil {
+				return
+			}
+			var value interface{}
+			if anyRoot {
+				value, err = validator.ValidateJSONValue(&body, requestOptions(r, acceptLanguage, nil)...)
+			} else {
+				value, err = validator.ValidateJSON(&body, requestOptions(r, acceptLanguage, nil)...)
 			}
-			dataMap, err := ValidateJSON(&body, schema)
 			if err != nil {
 				errorHandler(w, r, err)
 				return
 			}
 			r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyBody, dataMap)))
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyBody, value)))
 		}
 		return http.HandlerFunc(fn)
 	}
 }
 
+// ValidateBodyInto same as ValidateBody, but decode the validated body into a new value of the type of dst,
+// and save the pointer to it to the request's context with ContextKeyBody.
+// The dst can be a value or a pointer of the type, such as User{} or &User{}, and it's only used for the type.
+// It panics when dst is nil, which has no type.
+func ValidateBodyInto(schema Schema, dst interface{}, errorHandler func(http.ResponseWriter, *http.Request, error), opts ...Option) func(next http.Handler) http.Handler {
+	typ := reflect.TypeOf(dst)
+	if typ == nil {
+		panic("jio: the dst of ValidateBodyInto must be a value or a pointer of the type, such as User{} or &User{}")
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	o := newOptions(opts)
+	acceptLanguage := o.acceptLanguage
+	o.route.register("body", schema)
+	validator := Compile(schema, opts...)
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			body, err := readJSONBody(r)
+			if err != nil {
+				return
+			}
+			value := reflect.New(typ).Interface()
+			err = validator.ValidateJSONInto(&body, value, requestOptions(r, acceptLanguage, nil)...)
+			if err != nil {
+				errorHandler(w, r, err)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyBody, value)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// readJSONBody read the request's body if it's json, otherwise returns an empty body.
+func readJSONBody(r *http.Request) (body []byte, err error) {
+	if strings.Contains(r.Header.Get("Content-type"), "application/json") {
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		r.Body.Close()
+	}
+	return
+}
+
 // ValidateQuery validate the request's query using the schema.
-func ValidateQuery(schema Schema, errorHandler func(http.ResponseWriter, *http.Request, error)) func(next http.Handler) http.Handler {
+// The options are applied to every validation, same as ValidateJSON.
+// The schema is recorded to the OpenAPI document if the Route option of OpenAPI is used.
+func ValidateQuery(schema Schema, errorHandler func(http.ResponseWriter, *http.Request, error), opts ...Option) func(next http.Handler) http.Handler {
+	o := newOptions(opts)
+	acceptLanguage := o.acceptLanguage
+	o.route.register("query", schema)
+	validator := Compile(schema, opts...)
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			query := make(map[string]interface{})
 			for key, value := range r.URL.Query() {
 				query[key] = value[0]
 			}
-			ctx := NewContext(query)
-			schema.Validate(ctx)
-			if ctx.Err != nil {
-				errorHandler(w, r, ctx.Err)
+			value, err := validator.Validate(query, requestOptions(r, acceptLanguage, nil)...)
+			if err != nil {
+				errorHandler(w, r, err)
 				return
 			}
-			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyQuery, ctx.Value)))
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyQuery, value)))
 		}
 		return http.HandlerFunc(fn)
 	}
 }
+
+// requestOptions append the message catalog negotiated by the Accept-Language header of the request.
+func requestOptions(r *http.Request, acceptLanguage bool, opts []Option) []Option {
+	if !acceptLanguage {
+		return opts
+	}
+	messages, ok := negotiateMessages(r.Header.Get("Accept-Language"))
+	if !ok {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], UseMessages(messages))
+}
@@ -1,12 +1,15 @@
 package jio
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -46,7 +49,7 @@ func TestValidateBody(t *testing.T) {
 		"window": Object().Keys(K{
 			"title": String().Min(3).Max(18).Required(),
 			"size":  Array().Items(Number().Integer()).Length(2).Required(),
-		}).Without("name", "title").Required(),
+		}).WithoutPeers("name", "title").Required(),
 	})
 	handler := ValidateBody(schema, DefaultErrorHandler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "ok")
@@ -134,3 +137,237 @@ func TestValidateQuery(t *testing.T) {
 		t.Error("should bad request")
 	}
 }
+
+func TestValidateJSON_AbortEarly(t *testing.T) {
+	schema := Object().Keys(K{
+		"name": String().Min(3).Alphanum().Required(),
+		"age":  Number().Integer().Min(0),
+		"tags": Array().Items(String()),
+		"window": Object().Keys(K{
+			"title": String().Required(),
+		}),
+	})
+	data := []byte(`{"name": "#", "age": -1.5, "tags": [1, "ok", 2], "window": {}}`)
+	_, err := ValidateJSON(&data, schema, AbortEarly(false))
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatal("should be ValidationErrors")
+	}
+	rules := make(map[string]int)
+	for _, e := range errs {
+		rules[e.Path+" "+e.Rule]++
+	}
+	expected := map[string]int{
+		"name string.min":           1,
+		"name string.alphanum":      1,
+		"age number.integer":        1,
+		"age number.min":            1,
+		"tags.0 string.base":        1,
+		"tags.2 string.base":        1,
+		"window.title any.required": 1,
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("unexpected errors %v", rules)
+	}
+
+	data = []byte(`{"name": "#", "age": -1.5}`)
+	_, err = ValidateJSON(&data, schema)
+	if _, ok := err.(*ValidationError); !ok {
+		t.Error("should abort early by default")
+	}
+}
+
+func TestValidateBody_Concurrent(t *testing.T) {
+	schema := Object().Keys(K{
+		"name":  String().Trim().Min(3).Required(),
+		"tags":  Array().Items(String().Lowercase()).Default([]interface{}{"NEW"}),
+		"extra": Object().Default(map[string]interface{}{"count": 0}).Keys(K{"count": Number().Integer()}),
+	})
+	handler := ValidateBody(schema, DefaultErrorHandler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, expected := `{"name": " faceair "}`, `{"extra":{"count":0},"name":"faceair","tags":["new"]}`
+			if i%2 == 0 {
+				body, expected = `{"name": "hi"}`, `{"message":"field `+"`name`"+` value hi length less than 3"}`
+			}
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Body.String() != expected {
+				t.Errorf("unexpected body %s", w.Body.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestValidateJSONValue(t *testing.T) {
+	data := []byte(`[{"name": " faceair "}, {"name": "jio"}]`)
+	value, err := ValidateJSONValue(&data, Array().Items(Object().Keys(K{
+		"name": String().Trim().Required(),
+	})).Min(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(value.([]interface{})) != 2 || string(data) != `[{"name":"faceair"},{"name":"jio"}]` {
+		t.Error("should validate array root")
+	}
+
+	data = []byte(`" JIO "`)
+	value, err = ValidateJSONValue(&data, String().Trim().Lowercase())
+	if err != nil || value != "jio" || string(data) != `"jio"` {
+		t.Error("should validate string root")
+	}
+
+	data = []byte(`[1, "2"]`)
+	_, err = ValidateJSONValue(&data, Array().Items(Number()))
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Path != "1" {
+		t.Error("should throw the item error")
+	}
+
+	data = []byte(`[1]`)
+	if _, err := ValidateJSON(&data, Any()); err == nil {
+		t.Error("ValidateJSON should only accept object")
+	}
+}
+
+func TestValidateBody_AnyRoot(t *testing.T) {
+	schema := Array().Items(Number().ParseString()).Max(2)
+	var value interface{}
+	handler := ValidateBody(schema, DefaultErrorHandler, AnyRoot())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value = r.Context().Value(ContextKeyBody)
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`["1", 2]`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Body.String() != `[1,2]` || !reflect.DeepEqual(value, []interface{}{1.0, 2.0}) {
+		t.Error("should accept array body")
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[1, 2, 3]`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Error("should bad request")
+	}
+}
+
+func TestValidateJSON_UseNumber(t *testing.T) {
+	schema := Object().Keys(K{
+		"id":    Number().Integer().Min(0).Required(),
+		"price": Number().Round(),
+	})
+	data := []byte(`{"id": 12345678901234567891, "price": 1.5}`)
+	dataMap, err := ValidateJSON(&data, schema, UseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataMap["id"] != json.Number("12345678901234567891") || string(data) != `{"id":12345678901234567891,"price":2}` {
+		t.Error("should keep the digits")
+	}
+
+	data = []byte(`{"id": 12345678901234567891}`)
+	if _, err := Compile(schema).ValidateJSON(&data, UseNumber()); err != nil || string(data) != `{"id":12345678901234567891}` {
+		t.Error("should use number with the compiled schema")
+	}
+
+	data = []byte(`{"id": 1} {}`)
+	if _, err := ValidateJSON(&data, schema, UseNumber()); err == nil {
+		t.Error("should reject the trailing data")
+	}
+}
+
+func TestValidateJSONInto(t *testing.T) {
+	type Tag struct {
+		Name string `json:"name"`
+	}
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+		Tags []Tag  `json:"tags"`
+	}
+	schema := Object().Keys(K{
+		"name": String().Trim().Required(),
+		"age":  Number().Default(18),
+		"tags": Array().Items(Any()),
+	})
+
+	data := []byte(`{"name": " faceair ", "tags": [{"name": "a"}]}`)
+	var user User
+	if err := ValidateJSONInto(&data, schema, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "faceair" || user.Age != 18 || len(user.Tags) != 1 || user.Tags[0].Name != "a" {
+		t.Error("should decode the validated value")
+	}
+
+	data = []byte(`{"name": "faceair", "tags": [{"name": "a"}, {"name": 1}]}`)
+	err := ValidateJSONInto(&data, schema, &user)
+	validationErr, ok := err.(*ValidationError)
+	if !ok || validationErr.Rule != "any.decode" || validationErr.Path != "tags.1.name" || validationErr.Value != 1.0 {
+		t.Fatal("should throw the decode error")
+	}
+	if validationErr.Error() != "field `tags.1.name` value 1 cannot be decoded into string" {
+		t.Error("unexpected message")
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Error("should unwrap the type error")
+	}
+
+	data = []byte(`{}`)
+	if err := ValidateJSONInto(&data, schema, &user); err == nil {
+		t.Error("should validate before decoding")
+	}
+}
+
+func TestValidateBodyInto(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+	schema := Object().Keys(K{
+		"name": String().Lowercase().Required(),
+	})
+	var user *User
+	handler := ValidateBodyInto(schema, User{}, DefaultErrorHandler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = r.Context().Value(ContextKeyBody).(*User)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "JIO"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if user == nil || user.Name != "jio" {
+		t.Error("should save the decoded struct")
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Error("should bad request")
+	}
+}
+
+func TestValidateBodyInto_NilDst(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.HasPrefix(r.(string), "jio: the dst of ValidateBodyInto") {
+			t.Error("should panic on the nil dst", r)
+		}
+	}()
+	ValidateBodyInto(Object(), nil, DefaultErrorHandler)
+}
@@ -0,0 +1,786 @@
+package jio
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema export the schema as a JSON Schema of draft 2020-12, which can be encoded by encoding/json.
+// The keys, lengths, limits, patterns, formats, Valid, Equal and Default are exported as the JSON Schema keywords.
+// The lengths of the strings are counted in bytes by jio, so they're exported as `x-jio-minBytes` and `x-jio-maxBytes`
+// instead of minLength and maxLength which count the code points.
+// The rules that can't be expressed by JSON Schema, such as Lowercase and When, are listed in the `x-jio-rules` keyword,
+// and the functions of Transform, Check and Convert are listed in the `x-jio-opaque` keyword,
+// so that the readers know the value is further validated or changed by the server.
+// The metadata of the annotation keywords such as title and description is exported too.
+// The optional values are nullable, since null is treated as absent by jio.
+// The schemas of Link are exported once in `$defs` and referenced by their names.
+func JSONSchema(schema Schema) map[string]interface{} {
+	exporter := newJSONSchemaExporter("#/$defs/")
+	result := exporter.export(schema)
+	result["$schema"] = jsonSchemaDialect
+	if len(exporter.defs) > 0 {
+		result["$defs"] = exporter.defs
+	}
+	return result
+}
+
+// jsonSchemaExporter export the schemas, the schemas of Link are collected in defs and referenced with the prefix.
+type jsonSchemaExporter struct {
+	prefix string
+	defs   map[string]interface{}
+}
+
+func newJSONSchemaExporter(prefix string) *jsonSchemaExporter {
+	return &jsonSchemaExporter{prefix: prefix, defs: map[string]interface{}{}}
+}
+
+// export export the schema without the $schema keyword, used for the nested schemas.
+func (e *jsonSchemaExporter) export(schema Schema) map[string]interface{} {
+	if typed, ok := schema.(interface{ Schema() Schema }); ok {
+		schema = typed.Schema()
+	}
+	if link, ok := schema.(*LinkSchema); ok {
+		if _, ok := e.defs[link.name]; !ok {
+			// the placeholder stops the recursion of the schemas linking to themselves.
+			e.defs[link.name] = nil
+			e.defs[link.name] = e.export(link.resolve())
+		}
+		return map[string]interface{}{"$ref": e.prefix + escapeJSONPointer(link.name)}
+	}
+	result := map[string]interface{}{}
+	s, ok := schema.(interface{ base() *baseSchema })
+	if !ok {
+		result["x-jio-opaque"] = []string{"validate"}
+		return result
+	}
+	b := s.base()
+
+	var typ string
+	switch schema.(type) {
+	case *StringSchema:
+		typ = "string"
+	case *NumberSchema:
+		typ = "number"
+	case *BoolSchema:
+		typ = "boolean"
+	case *ArraySchema:
+		typ = "array"
+	case *ObjectSchema:
+		typ = "object"
+	case *DateSchema:
+		date := schema.(*DateSchema)
+		// the numbers are accepted too when Timestamp is set, which leaves the type open.
+		if date.timestamp == "" {
+			typ = "string"
+		}
+		if len(date.layouts) == 0 {
+			result["format"] = "date-time"
+		}
+	}
+
+	var rules, opaque []string
+	for _, desc := range b.descs {
+		switch desc.name {
+		case "required", "optional":
+		case "default":
+			if desc.args["value"] != nil {
+				result["default"] = desc.args["value"]
+			}
+		case "equal":
+			result["const"] = desc.args["expected"]
+		case "valid":
+			result["enum"] = toInterfaces(desc.args["valids"])
+		case "transform", "check", "convert":
+			opaque = append(opaque, desc.name)
+		case "min", "max", "length":
+			switch schema.(type) {
+			case *DateSchema, *DurationSchema, *ByteSizeSchema:
+				// the limits of the dates and the quantities aren't the limits of the JSON values.
+				rules = append(rules, desc.name)
+				continue
+			}
+			limit := desc.args["limit"]
+			var minKeyword, maxKeyword string
+			switch typ {
+			case "string":
+				// jio counts the bytes of the strings, while minLength and maxLength count the code points.
+				minKeyword, maxKeyword = "x-jio-minBytes", "x-jio-maxBytes"
+			case "array":
+				minKeyword, maxKeyword = "minItems", "maxItems"
+			default:
+				minKeyword, maxKeyword = "minimum", "maximum"
+			}
+			if desc.name != "max" {
+				setLimit(result, minKeyword, limit, 1)
+			}
+			if desc.name != "min" {
+				setLimit(result, maxKeyword, limit, -1)
+			}
+		case "regex", "alphanum", "token":
+			if _, ok := result["pattern"]; !ok {
+				result["pattern"] = desc.args["regex"]
+			} else {
+				appendAllOf(result, map[string]interface{}{"pattern": desc.args["regex"]})
+			}
+		case "email", "uuid", "uri", "hostname", "ip":
+			format := desc.name
+			if versions, _ := desc.args["versions"].([]int); desc.name == "ip" {
+				if len(versions) != 1 {
+					rules = append(rules, desc.name)
+					continue
+				}
+				format += "v" + strconv.Itoa(versions[0])
+			}
+			if _, ok := result["format"]; !ok {
+				result["format"] = format
+			} else {
+				appendAllOf(result, map[string]interface{}{"format": format})
+			}
+		case "base64":
+			if desc.args["urlSafe"] == true {
+				rules = append(rules, desc.name)
+			} else {
+				result["contentEncoding"] = "base64"
+			}
+		case "greater":
+			setLimit(result, "exclusiveMinimum", desc.args["limit"], 1)
+		case "less":
+			setLimit(result, "exclusiveMaximum", desc.args["limit"], -1)
+		case "integer":
+			typ = "integer"
+		case "try", "oneOf", "allOf":
+			schemas := desc.args["schemas"].([]Schema)
+			docs := make([]interface{}, 0, len(schemas))
+			for _, item := range schemas {
+				docs = append(docs, e.export(item))
+			}
+			keyword := desc.name
+			if keyword == "try" {
+				keyword = "anyOf"
+			}
+			if _, ok := result[keyword]; !ok {
+				result[keyword] = docs
+			} else {
+				appendAllOf(result, map[string]interface{}{keyword: docs})
+			}
+		case "discriminator":
+			key := desc.args["key"].(string)
+			cases := desc.args["cases"].(map[string]Schema)
+			values := make([]string, 0, len(cases))
+			for value := range cases {
+				values = append(values, value)
+			}
+			sort.Strings(values)
+			docs := make([]interface{}, 0, len(values))
+			for _, value := range values {
+				docs = append(docs, e.export(cases[value]))
+			}
+			if _, ok := result["oneOf"]; !ok {
+				result["oneOf"] = docs
+			} else {
+				appendAllOf(result, map[string]interface{}{"oneOf": docs})
+			}
+			// the discriminator keyword of OpenAPI, which is an annotation for JSON Schema.
+			result["discriminator"] = map[string]interface{}{"propertyName": key}
+			addRequired(result, key)
+		case "additionalKeys":
+			if additional, ok := desc.args["schema"].(Schema); ok && additional != nil {
+				result["additionalProperties"] = e.export(additional)
+			} else {
+				result["additionalProperties"] = false
+			}
+		case "items":
+			schemas, _ := desc.args["schemas"].([]Schema)
+			var items map[string]interface{}
+			switch len(schemas) {
+			case 0:
+				continue
+			case 1:
+				items = e.export(schemas[0])
+			default:
+				anyOf := make([]interface{}, 0, len(schemas))
+				for _, item := range schemas {
+					anyOf = append(anyOf, e.export(item))
+				}
+				items = map[string]interface{}{"anyOf": anyOf}
+			}
+			if _, ok := result["items"]; !ok {
+				result["items"] = items
+			} else {
+				appendAllOf(result, map[string]interface{}{"items": items})
+			}
+		case "keys":
+			properties, _ := result["properties"].(map[string]interface{})
+			if properties == nil {
+				properties = map[string]interface{}{}
+				result["properties"] = properties
+			}
+			for key, child := range desc.args["keys"].(K) {
+				properties[key] = e.export(child)
+				if isRequired(child) {
+					addRequired(result, key)
+				}
+			}
+		case "with":
+			for _, key := range desc.args["keys"].([]string) {
+				addRequired(result, key)
+			}
+		case "without":
+			not, _ := result["not"].(map[string]interface{})
+			if not == nil {
+				not = map[string]interface{}{"anyOf": []interface{}{}}
+				result["not"] = not
+			}
+			for _, key := range desc.args["keys"].([]string) {
+				not["anyOf"] = append(not["anyOf"].([]interface{}), map[string]interface{}{"required": []string{key}})
+			}
+		case "withoutPeers":
+			peers := desc.args["peers"].([]string)
+			anyOf := make([]interface{}, 0, len(peers))
+			for _, peer := range peers {
+				anyOf = append(anyOf, map[string]interface{}{"required": []string{peer}})
+			}
+			dependentSchemas, _ := result["dependentSchemas"].(map[string]interface{})
+			if dependentSchemas == nil {
+				dependentSchemas = map[string]interface{}{}
+				result["dependentSchemas"] = dependentSchemas
+			}
+			dependentSchemas[desc.args["key"].(string)] = map[string]interface{}{"not": map[string]interface{}{"anyOf": anyOf}}
+		default:
+			rules = append(rules, desc.name)
+		}
+	}
+
+	for key, value := range b.meta {
+		if jsonSchemaAnnotations[key] && key != "$defs" && key != "definitions" {
+			result[key] = value
+		}
+	}
+	if typ != "" {
+		if isRequired(schema) {
+			result["type"] = typ
+		} else {
+			result["type"] = []string{typ, "null"}
+		}
+	}
+	if required, ok := result["required"].([]string); ok {
+		sort.Strings(required)
+	}
+	if len(rules) > 0 {
+		result["x-jio-rules"] = rules
+	}
+	if len(opaque) > 0 {
+		result["x-jio-opaque"] = opaque
+	}
+	return result
+}
+
+// isRequired check if the schema is marked as required.
+func isRequired(schema Schema) bool {
+	if typed, ok := schema.(interface{ Schema() Schema }); ok {
+		schema = typed.Schema()
+	}
+	if link, ok := schema.(*LinkSchema); ok {
+		return link.required
+	}
+	s, ok := schema.(interface{ base() *baseSchema })
+	return ok && s.base().required != nil && *s.base().required
+}
+
+// setLimit set the keyword to the limit if it's stricter than the current one,
+// sign is 1 for the lower limits and -1 for the upper limits.
+func setLimit(result map[string]interface{}, keyword string, limit interface{}, sign float64) {
+	if current, ok := result[keyword]; ok {
+		if sign*(toFloat(limit)-toFloat(current)) <= 0 {
+			return
+		}
+	}
+	result[keyword] = limit
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func addRequired(result map[string]interface{}, key string) {
+	required, _ := result["required"].([]string)
+	for _, k := range required {
+		if k == key {
+			return
+		}
+	}
+	result["required"] = append(required, key)
+}
+
+func appendAllOf(result map[string]interface{}, schema map[string]interface{}) {
+	allOf, _ := result["allOf"].([]interface{})
+	result["allOf"] = append(allOf, schema)
+}
+
+// toInterfaces convert the slice of any type to []interface{}.
+func toInterfaces(values interface{}) []interface{} {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result
+}
+
+// FromJSONSchema build a schema from the JSON Schema document decoded by encoding/json,
+// so that the contracts written in JSON Schema can be enforced with ValidateBody.
+// The keywords type, enum, const, properties, required, additionalProperties, items, minItems, maxItems,
+// minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, allOf, anyOf, oneOf
+// and the local $ref such as `#/$defs/user` are supported, the annotations such as title are ignored.
+// The byte lengths `x-jio-minBytes` and `x-jio-maxBytes` exported by JSONSchema are supported too,
+// so that a schema validates the same after the export and the import.
+// The formats email, uuid, uri, hostname, ipv4 and ipv6 and the base64 contentEncoding of the strings are validated,
+// the other formats are ignored.
+// When the type is absent, it's inferred from the keywords, for example minLength implies string.
+// An error listing the unsupported or invalid keywords by their json pointers is returned if there are any.
+func FromJSONSchema(doc map[string]interface{}) (Schema, error) {
+	importer := &jsonSchemaImporter{root: doc, refs: map[string]Schema{}}
+	schema := importer.schema(doc, "")
+	sort.Strings(importer.unsupported)
+	sort.Strings(importer.invalid)
+	var problems []string
+	if len(importer.unsupported) > 0 {
+		problems = append(problems, "unsupported json schema keywords: "+strings.Join(importer.unsupported, ", "))
+	}
+	if len(importer.invalid) > 0 {
+		problems = append(problems, "invalid json schema keywords: "+strings.Join(importer.invalid, ", "))
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("jio: %s", strings.Join(problems, "; "))
+	}
+	return schema, nil
+}
+
+var jsonSchemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$defs": true, "definitions": true,
+	"title": true, "description": true, "default": true, "examples": true, "deprecated": true,
+	"readOnly": true, "writeOnly": true, "format": true, "contentEncoding": true, "contentMediaType": true,
+	"discriminator": true,
+}
+
+// jsonSchemaKeywords are the supported keywords grouped by the type they imply.
+var jsonSchemaKeywords = map[string]string{
+	"type": "", "enum": "", "const": "", "allOf": "", "anyOf": "", "oneOf": "", "$ref": "",
+	"minLength": "string", "maxLength": "string", "pattern": "string", "x-jio-minBytes": "string", "x-jio-maxBytes": "string",
+	"minimum": "number", "maximum": "number", "exclusiveMinimum": "number", "exclusiveMaximum": "number",
+	"properties": "object", "required": "object", "additionalProperties": "object",
+	"items": "array", "minItems": "array", "maxItems": "array",
+}
+
+type jsonSchemaImporter struct {
+	root        map[string]interface{}
+	refs        map[string]Schema
+	unsupported []string
+	invalid     []string
+}
+
+func (im *jsonSchemaImporter) schema(doc interface{}, pointer string) Schema {
+	switch v := doc.(type) {
+	case bool:
+		if !v {
+			im.unsupported = append(im.unsupported, "#"+pointer)
+		}
+		return Any()
+	case map[string]interface{}:
+		return im.object(v, pointer)
+	}
+	im.invalid = append(im.invalid, "#"+pointer)
+	return Any()
+}
+
+func (im *jsonSchemaImporter) object(doc map[string]interface{}, pointer string) Schema {
+	keywords := make([]string, 0, len(doc))
+	for keyword := range doc {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	inferred := map[string]bool{}
+	for _, keyword := range keywords {
+		typ, ok := jsonSchemaKeywords[keyword]
+		switch {
+		case ok && typ != "":
+			inferred[typ] = true
+		case !ok && !jsonSchemaAnnotations[keyword]:
+			im.unsupported = append(im.unsupported, "#"+pointer+"/"+escapeJSONPointer(keyword))
+		}
+	}
+
+	var types []string
+	nullable := false
+	switch typ := doc["type"].(type) {
+	case nil:
+		for _, t := range []string{"string", "number", "object", "array"} {
+			if inferred[t] {
+				types = append(types, t)
+			}
+		}
+	case string:
+		types = []string{typ}
+	case []interface{}:
+		for _, t := range typ {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		im.invalid = append(im.invalid, "#"+pointer+"/type")
+	}
+
+	var parts []Schema
+	var typed []Schema
+	for _, typ := range types {
+		if typ == "null" {
+			nullable = true
+			continue
+		}
+		typed = append(typed, im.typed(typ, doc, pointer))
+	}
+	switch {
+	case len(typed) == 1:
+		parts = append(parts, typed[0])
+	case len(typed) > 1:
+		parts = append(parts, Alternatives().Try(typed...))
+	case nullable:
+		parts = append(parts, Any().Valid(nil))
+	}
+
+	if ref, ok := doc["$ref"].(string); ok {
+		parts = append(parts, im.ref(ref, pointer+"/$ref"))
+	}
+	if values, ok := doc["enum"].([]interface{}); ok {
+		parts = append(parts, Any().Transform(func(ctx *Context) {
+			for _, value := range values {
+				if jsonSchemaEqual(value, ctx.Value) {
+					return
+				}
+			}
+			ctx.failRule("any.valid", map[string]interface{}{"valids": values})
+		}))
+		parts[len(parts)-1].(*AnySchema).describeLast("valid", map[string]interface{}{"valids": values})
+	}
+	if value, ok := doc["const"]; ok {
+		parts = append(parts, Any().Transform(func(ctx *Context) {
+			if !jsonSchemaEqual(value, ctx.Value) {
+				ctx.failRule("any.equal", map[string]interface{}{"expected": value})
+			}
+		}))
+		parts[len(parts)-1].(*AnySchema).describeLast("equal", map[string]interface{}{"expected": value})
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		docs, ok := doc[keyword].([]interface{})
+		if !ok {
+			if doc[keyword] != nil {
+				im.invalid = append(im.invalid, "#"+pointer+"/"+keyword)
+			}
+			continue
+		}
+		schemas := make([]Schema, 0, len(docs))
+		for i, sub := range docs {
+			schemas = append(schemas, im.schema(sub, pointer+"/"+keyword+"/"+strconv.Itoa(i)))
+		}
+		switch keyword {
+		case "allOf":
+			parts = append(parts, Alternatives().AllOf(schemas...))
+		case "anyOf":
+			parts = append(parts, Alternatives().Try(schemas...))
+		default:
+			parts = append(parts, Alternatives().OneOf(schemas...))
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return Any()
+	case 1:
+		return parts[0]
+	}
+	return Alternatives().AllOf(parts...)
+}
+
+// ref returns a schema validating with the schema at the local json pointer, the schema is built once and shared.
+func (im *jsonSchemaImporter) ref(ref, pointer string) Schema {
+	if _, ok := im.refs[ref]; !ok {
+		target, ok := resolveJSONPointer(im.root, ref)
+		if !ok {
+			im.unsupported = append(im.unsupported, "#"+pointer)
+			return Any()
+		}
+		// the placeholder allows the recursive references.
+		im.refs[ref] = nil
+		im.refs[ref] = im.schema(target, strings.TrimPrefix(ref, "#"))
+	}
+	return Link(ref, func() Schema { return im.refs[ref] })
+}
+
+func (im *jsonSchemaImporter) typed(typ string, doc map[string]interface{}, pointer string) Schema {
+	switch typ {
+	case "string":
+		schema := String()
+		if limit, ok := im.length(doc, "minLength", pointer); ok {
+			schema = schema.check("string.min", map[string]interface{}{"limit": limit}, func(value string) bool {
+				return utf8.RuneCountInString(value) >= limit
+			})
+		}
+		if limit, ok := im.length(doc, "maxLength", pointer); ok {
+			schema = schema.check("string.max", map[string]interface{}{"limit": limit}, func(value string) bool {
+				return utf8.RuneCountInString(value) <= limit
+			})
+		}
+		if limit, ok := im.length(doc, "x-jio-minBytes", pointer); ok {
+			schema = schema.Min(limit)
+		}
+		if limit, ok := im.length(doc, "x-jio-maxBytes", pointer); ok {
+			schema = schema.Max(limit)
+		}
+		if pattern, ok := doc["pattern"]; ok {
+			regex, ok := pattern.(string)
+			if _, err := regexp.Compile(regex); !ok || err != nil {
+				im.invalid = append(im.invalid, "#"+pointer+"/pattern")
+			} else {
+				schema = schema.Regex(regex)
+			}
+		}
+		// the unknown formats are annotations.
+		if format, ok := doc["format"].(string); ok {
+			schema, _ = stringFormat(schema, format)
+		}
+		if doc["contentEncoding"] == "base64" {
+			schema = schema.Base64(false)
+		}
+		return schema
+	case "number", "integer":
+		schema := Number()
+		if typ == "integer" {
+			schema = schema.Integer()
+		}
+		for _, keyword := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum"} {
+			value, ok := doc[keyword]
+			if !ok {
+				continue
+			}
+			limit, ok := jsonSchemaNumber(value)
+			if !ok {
+				im.invalid = append(im.invalid, "#"+pointer+"/"+keyword)
+				continue
+			}
+			switch keyword {
+			case "minimum":
+				schema = schema.Min(limit)
+			case "maximum":
+				schema = schema.Max(limit)
+			case "exclusiveMinimum":
+				schema = schema.Greater(limit)
+			default:
+				schema = schema.Less(limit)
+			}
+		}
+		return schema
+	case "boolean":
+		return Bool()
+	case "object":
+		schema := Object()
+		properties, _ := doc["properties"].(map[string]interface{})
+		if doc["properties"] != nil && properties == nil {
+			im.invalid = append(im.invalid, "#"+pointer+"/properties")
+		}
+		required := map[string]bool{}
+		if keys, ok := doc["required"].([]interface{}); ok {
+			for _, key := range keys {
+				if s, ok := key.(string); ok {
+					required[s] = true
+				}
+			}
+		} else if doc["required"] != nil {
+			im.invalid = append(im.invalid, "#"+pointer+"/required")
+		}
+		keys := K{}
+		known := make([]string, 0, len(properties))
+		for key, sub := range properties {
+			known = append(known, key)
+			keys[key] = im.schema(sub, pointer+"/properties/"+escapeJSONPointer(key))
+		}
+		for key := range required {
+			if keys[key] == nil {
+				keys[key] = Any()
+			}
+			keys[key] = requiredSchema(keys[key])
+		}
+		if len(keys) > 0 {
+			schema = schema.Keys(keys)
+		}
+		switch additional := doc["additionalProperties"].(type) {
+		case nil:
+		case bool:
+			if !additional {
+				sort.Strings(known)
+				schema = schema.additionalKeys(known, nil)
+			}
+		default:
+			sort.Strings(known)
+			schema = schema.additionalKeys(known, im.schema(additional, pointer+"/additionalProperties"))
+		}
+		return schema
+	case "array":
+		schema := Array()
+		if items, ok := doc["items"]; ok {
+			schema = schema.Items(im.schema(items, pointer+"/items"))
+		}
+		if limit, ok := im.length(doc, "minItems", pointer); ok {
+			schema = schema.Min(limit)
+		}
+		if limit, ok := im.length(doc, "maxItems", pointer); ok {
+			schema = schema.Max(limit)
+		}
+		return schema
+	}
+	im.invalid = append(im.invalid, "#"+pointer+"/type")
+	return Any()
+}
+
+// length returns the non-negative integer of the keyword.
+func (im *jsonSchemaImporter) length(doc map[string]interface{}, keyword, pointer string) (int, bool) {
+	value, ok := doc[keyword]
+	if !ok {
+		return 0, false
+	}
+	limit, ok := jsonSchemaNumber(value)
+	if !ok || limit < 0 || limit != math.Trunc(limit) {
+		im.invalid = append(im.invalid, "#"+pointer+"/"+keyword)
+		return 0, false
+	}
+	return int(limit), true
+}
+
+func jsonSchemaNumber(value interface{}) (float64, bool) {
+	if v, ok := value.(int); ok {
+		return float64(v), true
+	}
+	return toFloat64(value)
+}
+
+// jsonSchemaEqual check if the value equals the value of enum or const, the numbers are compared by value,
+// so that the json.Number of UseNumber equals the float64 of the document.
+func jsonSchemaEqual(expected, value interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		v, ok := value.(map[string]interface{})
+		if !ok || len(v) != len(e) {
+			return false
+		}
+		for key, item := range e {
+			if other, ok := v[key]; !ok || !jsonSchemaEqual(item, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		v, ok := value.([]interface{})
+		if !ok || len(v) != len(e) {
+			return false
+		}
+		for i, item := range e {
+			if !jsonSchemaEqual(item, v[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if limit, ok := jsonSchemaNumber(expected); ok {
+		if _, ok := toFloat64(value); ok {
+			return compareNumber(value, limit) == 0
+		}
+		return false
+	}
+	return reflect.DeepEqual(expected, value)
+}
+
+// requiredSchema returns the required version of the schema.
+func requiredSchema(schema Schema) Schema {
+	switch s := schema.(type) {
+	case *AnySchema:
+		return s.Required()
+	case *StringSchema:
+		return s.Required()
+	case *NumberSchema:
+		return s.Required()
+	case *BoolSchema:
+		return s.Required()
+	case *ArraySchema:
+		return s.Required()
+	case *ObjectSchema:
+		return s.Required()
+	case *AlternativesSchema:
+		return s.Required()
+	case *LinkSchema:
+		return s.Required()
+	case *DateSchema:
+		return s.Required()
+	case *DurationSchema:
+		return s.Required()
+	case *ByteSizeSchema:
+		return s.Required()
+	}
+	return schema
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// resolveJSONPointer returns the value at the local json pointer such as `#/$defs/user`.
+func resolveJSONPointer(root interface{}, ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	value := root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return value, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
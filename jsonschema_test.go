@@ -0,0 +1,256 @@
+package jio
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := Object().Keys(K{
+		"name": String().Min(3).Max(10).Min(2).Alphanum().Lowercase().Required(),
+		"age":  Number().Integer().Min(0).Max(150).Default(18),
+		"role": String().Valid("admin", "user").Check(func(string) error { return nil }),
+		"tags": Array().Items(String(), Number()).Max(3),
+		"info": Object().Keys(K{
+			"active": Bool().Equal(true).Required(),
+		}).With("active").WithoutPeers("a", "b", "c"),
+		"level": TypedNumber[uint8](Number()).Valid(1, 2),
+		"ref":   Any().When("role", "admin", String().Required()),
+	}).Required()
+
+	data, err := json.Marshal(JSONSchema(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"properties": {
+			"age": {"default": 18, "maximum": 150, "minimum": 0, "type": ["integer", "null"]},
+			"info": {
+				"dependentSchemas": {"a": {"not": {"anyOf": [{"required": ["b"]}, {"required": ["c"]}]}}},
+				"properties": {"active": {"const": true, "type": "boolean"}},
+				"required": ["active"],
+				"type": ["object", "null"]
+			},
+			"level": {"enum": [1, 2], "minimum": 0, "type": ["integer", "null"]},
+			"name": {"pattern": "^[a-zA-Z0-9]+$", "type": "string", "x-jio-maxBytes": 10, "x-jio-minBytes": 3, "x-jio-rules": ["lowercase"]},
+			"ref": {"x-jio-rules": ["when"]},
+			"role": {"enum": ["admin", "user"], "type": ["string", "null"], "x-jio-opaque": ["check"]},
+			"tags": {"items": {"anyOf": [{"type": ["string", "null"]}, {"type": ["number", "null"]}]}, "maxItems": 3, "type": ["array", "null"]}
+		},
+		"required": ["name"],
+		"type": "object"
+	}`
+	var compact strings.Builder
+	for _, line := range strings.Split(expected, "\n") {
+		compact.WriteString(strings.ReplaceAll(strings.TrimSpace(line), ": ", ":"))
+	}
+	if string(data) != strings.ReplaceAll(compact.String(), ", ", ",") {
+		t.Errorf("unexpected json schema %s", data)
+	}
+}
+
+func TestJSONSchema_Opaque(t *testing.T) {
+	result := JSONSchema(String().Transform(func(*Context) {}).Regex("a").Regex("b"))
+	if opaque, ok := result["x-jio-opaque"].([]string); !ok || len(opaque) != 1 || opaque[0] != "transform" {
+		t.Error("should mark the transform as opaque")
+	}
+	if result["pattern"] != "a" || len(result["allOf"].([]interface{})) != 1 {
+		t.Error("should export all patterns")
+	}
+}
+
+func TestJSONSchema_Without(t *testing.T) {
+	data, _ := json.Marshal(JSONSchema(Object().Without("a").Without("b")))
+	if !strings.Contains(string(data), `"not":{"anyOf":[{"required":["a"]},{"required":["b"]}]}`) {
+		t.Error("should forbid the keys", string(data))
+	}
+}
+
+func TestJSONSchema_StringLength(t *testing.T) {
+	data, _ := json.Marshal(JSONSchema(String().Min(4).Max(6)))
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := FromJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, valid := range map[string]bool{"abcd": true, "中文": true, "中": false, "中文字": false, "abcdefg": false} {
+		ctx := NewContext(value)
+		schema.Validate(ctx)
+		if (ctx.Err == nil) != valid {
+			t.Error("should count the bytes after the export and the import", value)
+		}
+	}
+
+	schema, err = FromJSONSchema(map[string]interface{}{"type": "string", "maxLength": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext("中文")
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should count the code points of maxLength")
+	}
+}
+
+func TestFromJSONSchema(t *testing.T) {
+	var doc map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "user",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[a-z]+$"},
+			"age": {"type": ["integer", "null"], "minimum": 0, "exclusiveMaximum": 150},
+			"role": {"enum": ["admin", "user", {"custom": true}]},
+			"version": {"const": 1},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"contact": {"oneOf": [{"required": ["email"]}, {"required": ["phone"]}]},
+			"parent": {"$ref": "#/$defs/node"}
+		},
+		"required": ["name", "version"],
+		"additionalProperties": false,
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "parent": {"$ref": "#/$defs/node"}},
+				"required": ["name"]
+			}
+		}
+	}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := FromJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `{"name": "jio", "age": 1, "role": {"custom": true}, "version": 1, "tags": ["a"], "id": 1,
+		"contact": {"email": "a"}, "parent": {"name": "a", "parent": {"name": "b"}}}`
+	data := []byte(valid)
+	if _, err := ValidateJSON(&data, schema); err != nil {
+		t.Error(err)
+	}
+
+	for rule, patch := range map[string]string{
+		"any.required":     `{"version": 1}`,
+		"string.min":       `{"name": "j", "version": 1}`,
+		"string.max":       `{"name": "abcde", "version": 1}`,
+		"string.regex":     `{"name": "J1", "version": 1}`,
+		"number.integer":   `{"name": "jio", "age": 1.5, "version": 1}`,
+		"number.less":      `{"name": "jio", "age": 150, "version": 1}`,
+		"any.valid":        `{"name": "jio", "role": "root", "version": 1}`,
+		"any.equal":        `{"name": "jio", "version": 2}`,
+		"array.max":        `{"name": "jio", "version": 1, "tags": ["a", "b", "c"]}`,
+		"string.base":      `{"name": "jio", "version": 1, "id": true}`,
+		"alternatives.one": `{"name": "jio", "version": 1, "contact": {"email": "a", "phone": "b"}}`,
+		"object.unknown":   `{"name": "jio", "version": 1, "unknown": 1}`,
+	} {
+		data := []byte(patch)
+		_, err := ValidateJSON(&data, schema)
+		if validationErr, ok := err.(*ValidationError); !ok || validationErr.Rule != rule {
+			t.Errorf("should throw %s, got %v", rule, err)
+		}
+	}
+
+	data = []byte(`{"name": "jio", "version": 1, "parent": {"name": "a", "parent": {}}}`)
+	_, err = ValidateJSON(&data, schema)
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Path != "parent.parent.name" {
+		t.Error("should validate the recursive references")
+	}
+
+	data = []byte(`{"name": "你好", "version": 1}`)
+	if _, err := ValidateJSON(&data, schema, AbortEarly(false)); err == nil || strings.Contains(err.Error(), "length") {
+		t.Error("should count the characters")
+	}
+}
+
+func TestFromJSONSchema_RequiredRef(t *testing.T) {
+	var doc map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"required": ["user"],
+		"properties": {"user": {"$ref": "#/$defs/user"}},
+		"$defs": {"user": {"type": "object", "properties": {"name": {"type": "string"}}}}
+	}`), &doc)
+	schema, err := FromJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext(map[string]interface{}{})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" || err.Path != "user" {
+		t.Error("should require the referenced property", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"user": map[string]interface{}{"name": "jio"}})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should validate the referenced property", ctx.Err)
+	}
+	if required := JSONSchema(schema)["required"].([]string); len(required) != 1 || required[0] != "user" {
+		t.Error("should export the required link")
+	}
+}
+
+func TestFromJSONSchema_Error(t *testing.T) {
+	var doc map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"properties": {"a/b": {"multipleOf": 2}, "c": {"pattern": "["}, "d": {"$ref": "other.json"}},
+		"not": {}
+	}`), &doc)
+	_, err := FromJSONSchema(doc)
+	if err == nil || err.Error() != "jio: unsupported json schema keywords: #/not, #/properties/a~1b/multipleOf, #/properties/d/$ref; invalid json schema keywords: #/properties/c/pattern" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestJSONSchema_RoundTrip(t *testing.T) {
+	schema := Object().Keys(K{
+		"age": Number().Greater(0).Less(150).Required(),
+	})
+	data, _ := json.Marshal(JSONSchema(schema))
+	var doc map[string]interface{}
+	json.Unmarshal(data, &doc)
+	imported, err := FromJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for body, ok := range map[string]bool{`{"age": 1}`: true, `{"age": 0}`: false, `{}`: false} {
+		data := []byte(body)
+		if _, err := ValidateJSON(&data, imported); (err == nil) != ok {
+			t.Errorf("unexpected result of %s", body)
+		}
+	}
+}
+
+func TestFromJSONSchema_EnumNumber(t *testing.T) {
+	var doc map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"level": {"enum": [1, 2, {"max": 3}]},
+			"version": {"const": 1.5}
+		}
+	}`), &doc)
+	schema, err := FromJSONSchema(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range []string{`{"level": 2}`, `{"level": {"max": 3.0}}`, `{"version": 1.50}`} {
+		raw := []byte(data)
+		if _, err := ValidateJSON(&raw, schema, UseNumber()); err != nil {
+			t.Error("should compare the numbers by value", data, err)
+		}
+	}
+	for _, data := range []string{`{"level": 3}`, `{"level": "1"}`, `{"level": {"max": 4}}`} {
+		raw := []byte(data)
+		if _, err := ValidateJSON(&raw, schema, UseNumber()); err == nil {
+			t.Error("should reject the value not in enum", data)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package jio
+
+var _ Schema = new(LinkSchema)
+
+// defaultLinkDepth is the max depth of the nested Link schemas by default.
+const defaultLinkDepth = 32
+
+// Link Generates a schema validating with the schema returned by resolve, which is called on every validation,
+// so that a schema can reference itself, such as the replies of a comment:
+//
+//	var comment jio.Schema
+//	comment = jio.Object().Keys(jio.K{
+//	    "text":    jio.String().Required(),
+//	    "replies": jio.Array().Items(jio.Link("comment", func() jio.Schema { return comment })),
+//	})
+//
+// The name identifies the schema in the exports, such as the `$defs` of JSONSchema, it should be unique.
+// An error of the `link.depth` rule is thrown when the links are nested deeper than the max depth, 32 by default.
+func Link(name string, resolve func() Schema) *LinkSchema {
+	return &LinkSchema{name: name, resolve: resolve, maxDepth: defaultLinkDepth}
+}
+
+// LinkSchema validate the value with the linked schema.
+type LinkSchema struct {
+	name     string
+	resolve  func() Schema
+	maxDepth int
+	required bool
+}
+
+// MaxDepth set the max depth of the nested links, the depth counts every Link schema on the path of the value.
+func (l *LinkSchema) MaxDepth(depth int) *LinkSchema {
+	schema := *l
+	schema.maxDepth = depth
+	return &schema
+}
+
+// Required throws an error of the `any.required` rule when the value is absent,
+// whether the linked schema is required or not, such as a required key defined by a `$ref` in JSON Schema.
+func (l *LinkSchema) Required() *LinkSchema {
+	schema := *l
+	schema.required = true
+	return &schema
+}
+
+// Priority returns the default priority, the linked schema is not resolved before the validation.
+func (l *LinkSchema) Priority() int {
+	return 0
+}
+
+// Describe returns the description of the link with its name, the linked schema isn't described
+// since it may contain the link itself.
+func (l *LinkSchema) Describe() *Description {
+	description := &Description{
+		Type:  "link",
+		Rules: []RuleDescription{{Name: "link", Args: map[string]interface{}{"name": l.name, "maxDepth": l.maxDepth}}},
+	}
+	if l.required {
+		description.Flags = map[string]interface{}{"presence": "required"}
+	}
+	return description
+}
+
+// Validate a value using the linked schema
+func (l *LinkSchema) Validate(ctx *Context) {
+	if l.required && ctx.Value == nil {
+		ctx.abortRule("any.required", nil)
+		return
+	}
+	if ctx.Value != nil && ctx.depth >= l.maxDepth {
+		ctx.abortRule("link.depth", map[string]interface{}{"limit": l.maxDepth})
+		return
+	}
+	ctx.depth++
+	defer func() { ctx.depth-- }()
+	l.resolve().Validate(ctx)
+}
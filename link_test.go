@@ -0,0 +1,81 @@
+package jio
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func linkTestSchema() Schema {
+	var comment Schema
+	comment = Object().Keys(K{
+		"text":    String().Required(),
+		"replies": Array().Items(Link("comment", func() Schema { return comment })),
+	})
+	return comment
+}
+
+func TestLinkSchema(t *testing.T) {
+	schema := linkTestSchema()
+	ctx := NewContext(map[string]interface{}{
+		"text": "a",
+		"replies": []interface{}{
+			map[string]interface{}{"text": "b", "replies": []interface{}{map[string]interface{}{"text": "c"}}},
+		},
+	})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should validate the recursive schema")
+	}
+
+	ctx = NewContext(map[string]interface{}{
+		"text":    "a",
+		"replies": []interface{}{map[string]interface{}{"replies": []interface{}{}}},
+	})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Path != "replies.0.text" {
+		t.Error("should validate the linked schema")
+	}
+}
+
+func TestLinkSchema_MaxDepth(t *testing.T) {
+	var node Schema
+	node = Object().Keys(K{"child": Link("node", func() Schema { return node }).MaxDepth(2)})
+
+	ctx := NewContext(map[string]interface{}{"child": map[string]interface{}{"child": map[string]interface{}{}}})
+	node.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should allow the max depth")
+	}
+
+	ctx = NewContext(map[string]interface{}{"child": map[string]interface{}{"child": map[string]interface{}{"child": map[string]interface{}{}}}})
+	node.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "link.depth" || err.Path != "child.child.child" {
+		t.Error("should reject the values nested deeper than the max depth", ctx.Err)
+	}
+}
+
+func TestLinkSchema_Export(t *testing.T) {
+	schema := linkTestSchema()
+	result := JSONSchema(schema)
+	defs, _ := result["$defs"].(map[string]interface{})
+	comment, _ := defs["comment"].(map[string]interface{})
+	replies := result["properties"].(map[string]interface{})["replies"].(map[string]interface{})
+	if comment == nil || !reflect.DeepEqual(replies["items"], map[string]interface{}{"$ref": "#/$defs/comment"}) {
+		t.Error("should export the linked schema in $defs", result)
+	}
+
+	if description := Describe(Link("comment", nil)); description.Type != "link" || description.Rules[0].Args["name"] != "comment" {
+		t.Error("should describe the link")
+	}
+
+	value, err := Generate(schema, rand.NewSource(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext(value)
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should generate the recursive value")
+	}
+}
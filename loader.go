@@ -0,0 +1,1219 @@
+package jio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RuleFunc builds the custom rule registered to a Loader, args is the value of the rule in the document,
+// decoded as encoding/json does. The returned error is reported at the location of the rule.
+type RuleFunc func(args interface{}) (func(*Context), error)
+
+// Loader builds schemas from declarative documents, so that the limits can be changed without recompiling.
+// Load parses the json documents, the other formats such as yaml are decoded by the caller and passed to LoadMap.
+// The documents mirror the builder methods:
+//
+//	{
+//	  "type": "object",
+//	  "keys": {
+//	    "name": {"type": "string", "required": true, "trim": true, "min": 3, "max": 18},
+//	    "role": {"type": "string", "valid": ["admin", "guest"], "default": "guest"},
+//	    "age":  {"type": "number", "integer": true, "min": 0,
+//	             "when": {"ref": "role", "is": "admin", "then": {"type": "number", "min": 18}}},
+//	    "tags": {"type": "array", "max": 10, "items": {"type": "string", "rules": ["slug"]}}
+//	  }
+//	}
+//
+// The type is any, string, number, bool, array, object, alternatives, date, duration, byteSize or link,
+// and the keys of a schema are:
+//
+//	all types      required, default, priority, messages, meta, when, rules
+//	any            equal, valid
+//	string         trim, lowercase, uppercase, min, max, length, regex, alphanum, token, format, equal, valid
+//	number         parseString, ceil, floor, round, integer, min, max, greater, less, equal, valid
+//	bool           truthy, falsy, equal, valid
+//	array          items, min, max, length
+//	object         keys, discriminator, with, without, withoutPeers
+//	alternatives   try, oneOf, allOf
+//	date           layouts, timestamp, location, output, min, max, before, after
+//	duration       min, max, output
+//	byteSize       min, max, output
+//	link           name, maxDepth, required only
+//
+// The rules are added in the order above, whatever the order in the document, so the conversions
+// such as trim always run before the checks. The rules key lists the custom rules by name in order,
+// each rule is a name or an object with the name and the args, such as `{"name": "prefix", "args": "user_"}`.
+// The format is email, uuid, uri, hostname, ip, ipv4, ipv6, cidr, hex, base64, base64url or country.
+// The limits of date are RFC 3339 dates, durations relative to the time of the validation such as `-24h`,
+// or the reference paths of other dates, and the location is a name of time.LoadLocation such as `UTC`.
+// The durations are strings such as `1h30m` or numbers of seconds, and the sizes are strings such as `512MiB`
+// or numbers of bytes.
+// The defs key of the root document names the schemas, which are referenced by the link schemas with the name,
+// such as `{"type": "link", "name": "comment"}`, so that a schema can reference itself as Link does.
+// It's safe for concurrent use.
+type Loader struct {
+	mu    sync.RWMutex
+	rules map[string]RuleFunc
+}
+
+// NewLoader Generates a loader without custom rules.
+func NewLoader() *Loader {
+	return &Loader{rules: make(map[string]RuleFunc)}
+}
+
+// Register add the custom rule referenced by name in the rules of the documents.
+func (l *Loader) Register(name string, rule RuleFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules[name] = rule
+}
+
+func (l *Loader) rule(name string) (RuleFunc, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	rule, ok := l.rules[name]
+	return rule, ok
+}
+
+// Load builds the schema from the json document, the errors are *LoadError with the line and column of the bad value.
+func (l *Loader) Load(data []byte) (Schema, error) {
+	node, err := parseLoadNode(data)
+	if err != nil {
+		return nil, err
+	}
+	return (&loadBuilder{loader: l, data: data}).schema(node)
+}
+
+// LoadMap builds the schema from the decoded document, such as a yaml file decoded by a yaml package.
+// The errors are *LoadError with the json pointer of the bad value only, the line and the column are unknown
+// since the positions are lost by the decoding, jio doesn't parse yaml itself.
+func (l *Loader) LoadMap(doc map[string]interface{}) (Schema, error) {
+	return (&loadBuilder{loader: l}).schema(mapLoadNode(doc, ""))
+}
+
+// LoadError is the error of a bad declarative document.
+type LoadError struct {
+	// Pointer is the json pointer of the bad value, such as `/keys/name/min`.
+	Pointer string
+	// Line and Column are the position of the bad value, they're zero when the document is loaded by LoadMap.
+	Line   int
+	Column int
+	// Message tells what's wrong.
+	Message string
+}
+
+func (e *LoadError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("jio: #%s (line %d, column %d): %s", e.Pointer, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("jio: #%s: %s", e.Pointer, e.Message)
+}
+
+// loadNode is a value of the document with its location.
+type loadNode struct {
+	// value is nil, bool, string, a number, []*loadNode or *loadObject.
+	value   interface{}
+	pointer string
+	// offset is the byte offset in the json document, -1 when the document is loaded by LoadMap.
+	offset int
+}
+
+// loadObject keeps the keys in the order of the document.
+type loadObject struct {
+	keys   []string
+	values map[string]*loadNode
+}
+
+func mapLoadNode(value interface{}, pointer string) *loadNode {
+	node := &loadNode{pointer: pointer, offset: -1}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := &loadObject{values: make(map[string]*loadNode, len(v))}
+		for key, item := range v {
+			object.keys = append(object.keys, key)
+			object.values[key] = mapLoadNode(item, pointer+"/"+escapeJSONPointer(key))
+		}
+		sort.Strings(object.keys)
+		node.value = object
+	case map[interface{}]interface{}:
+		object := &loadObject{values: make(map[string]*loadNode, len(v))}
+		for k, item := range v {
+			key := fmt.Sprint(k)
+			object.keys = append(object.keys, key)
+			object.values[key] = mapLoadNode(item, pointer+"/"+escapeJSONPointer(key))
+		}
+		sort.Strings(object.keys)
+		node.value = object
+	case []interface{}:
+		items := make([]*loadNode, len(v))
+		for i, item := range v {
+			items[i] = mapLoadNode(item, fmt.Sprintf("%s/%d", pointer, i))
+		}
+		node.value = items
+	default:
+		node.value = value
+	}
+	return node
+}
+
+// loadParser parses the json document into nodes with the offsets of the values.
+type loadParser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+func parseLoadNode(data []byte) (*loadNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	p := &loadParser{data: data, dec: dec}
+	node, err := p.node("")
+	if err != nil {
+		return nil, err
+	}
+	if _, offset, err := p.token(""); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, p.errorAt("", offset, "unexpected data after the document")
+	}
+	return node, nil
+}
+
+// token read the next token and returns the offset where it starts.
+func (p *loadParser) token(pointer string) (json.Token, int, error) {
+	offset := int(p.dec.InputOffset())
+	for offset < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	token, err := p.dec.Token()
+	if err != nil && err != io.EOF {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			offset = int(syntaxErr.Offset)
+		}
+		return nil, offset, p.errorAt(pointer, offset, err.Error())
+	}
+	return token, offset, err
+}
+
+func (p *loadParser) node(pointer string) (*loadNode, error) {
+	token, offset, err := p.token(pointer)
+	if err == io.EOF {
+		return nil, p.errorAt(pointer, offset, "unexpected end of the document")
+	}
+	if err != nil {
+		return nil, err
+	}
+	node := &loadNode{pointer: pointer, offset: offset}
+	switch token {
+	case json.Delim('{'):
+		object := &loadObject{values: make(map[string]*loadNode)}
+		for p.dec.More() {
+			token, offset, err := p.token(pointer)
+			if err != nil {
+				return nil, err
+			}
+			key := token.(string)
+			if _, ok := object.values[key]; ok {
+				return nil, p.errorAt(pointer, offset, fmt.Sprintf("duplicate key %q", key))
+			}
+			child, err := p.node(pointer + "/" + escapeJSONPointer(key))
+			if err != nil {
+				return nil, err
+			}
+			object.keys = append(object.keys, key)
+			object.values[key] = child
+		}
+		node.value = object
+	case json.Delim('['):
+		items := []*loadNode{}
+		for p.dec.More() {
+			child, err := p.node(fmt.Sprintf("%s/%d", pointer, len(items)))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, child)
+		}
+		node.value = items
+	default:
+		node.value = token
+		return node, nil
+	}
+	if _, offset, err := p.token(pointer); err == io.EOF {
+		return nil, p.errorAt(pointer, offset, "unexpected end of the document")
+	} else if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (p *loadParser) errorAt(pointer string, offset int, message string) error {
+	line, column := position(p.data, offset)
+	return &LoadError{Pointer: pointer, Line: line, Column: column, Message: message}
+}
+
+// position returns the line and the column of the byte offset, both start from 1.
+func position(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line := 1 + bytes.Count(data[:offset], []byte{'\n'})
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
+
+// loadCommonKeys are the keys of all types, added before the keys of the type.
+var loadCommonKeys = []string{"required", "default", "priority", "messages", "meta"}
+
+// loadTypeKeys are the keys of each type in the order the rules are added.
+var loadTypeKeys = map[string][]string{
+	"any":          {"equal", "valid"},
+	"string":       {"trim", "lowercase", "uppercase", "min", "max", "length", "regex", "alphanum", "token", "format", "equal", "valid"},
+	"number":       {"parseString", "ceil", "floor", "round", "integer", "min", "max", "greater", "less", "equal", "valid"},
+	"bool":         {"truthy", "falsy", "equal", "valid"},
+	"array":        {"items", "min", "max", "length"},
+	"object":       {"keys", "discriminator", "with", "without", "withoutPeers"},
+	"alternatives": {"try", "oneOf", "allOf"},
+	"date":         {"layouts", "timestamp", "location", "output", "min", "max", "before", "after"},
+	"duration":     {"min", "max", "output"},
+	"byteSize":     {"min", "max", "output"},
+}
+
+// loadLinkKeys are the keys of the link schemas, which validate with the named schemas and have no rules of their own.
+var loadLinkKeys = []string{"name", "maxDepth", "required"}
+
+// loadBuilder builds the schemas from the nodes, data is the json document used to locate the errors.
+type loadBuilder struct {
+	loader *Loader
+	data   []byte
+	// defs are the nodes of the named schemas in the defs of the root document, linked are the schemas built from them.
+	defs   map[string]*loadNode
+	linked map[string]Schema
+}
+
+func (b *loadBuilder) errorf(node *loadNode, format string, args ...interface{}) error {
+	err := &LoadError{Pointer: node.pointer, Message: fmt.Sprintf(format, args...)}
+	if node.offset >= 0 {
+		err.Line, err.Column = position(b.data, node.offset)
+	}
+	return err
+}
+
+func (b *loadBuilder) schema(node *loadNode) (Schema, error) {
+	object, err := b.object(node)
+	if err != nil {
+		return nil, err
+	}
+	root := node.pointer == ""
+	if defsNode, ok := object.values["defs"]; ok && root {
+		if err := b.loadDefs(defsNode); err != nil {
+			return nil, err
+		}
+	}
+	typ := "any"
+	if typeNode, ok := object.values["type"]; ok {
+		if typ, err = b.stringValue(typeNode); err != nil {
+			return nil, err
+		}
+		if _, ok := loadTypeKeys[typ]; !ok && typ != "link" {
+			return nil, b.errorf(typeNode, "unknown type %q", typ)
+		}
+	}
+	keys := append(append(append([]string{}, loadCommonKeys...), loadTypeKeys[typ]...), "when", "rules")
+	if typ == "link" {
+		keys = loadLinkKeys
+	}
+	for _, key := range object.keys {
+		if key != "type" && !(key == "defs" && root) && !containsString(keys, key) {
+			return nil, b.errorf(object.values[key], "unknown key %q of the %s schema", key, typ)
+		}
+	}
+	if typ == "link" {
+		return b.link(node, object)
+	}
+
+	var schema Schema
+	switch typ {
+	case "any":
+		schema = Any()
+	case "string":
+		schema = String()
+	case "number":
+		schema = Number()
+	case "bool":
+		schema = Bool()
+	case "array":
+		schema = Array()
+	case "object":
+		schema = Object()
+	case "alternatives":
+		schema = Alternatives()
+	case "date":
+		schema = Date()
+	case "duration":
+		schema = Duration()
+	case "byteSize":
+		schema = ByteSize()
+	}
+	for _, key := range keys {
+		value, ok := object.values[key]
+		if !ok {
+			continue
+		}
+		if schema, err = b.apply(schema, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// apply add the rule of the key to the schema.
+func (b *loadBuilder) apply(schema Schema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "required":
+		required, err := b.boolValue(node)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return requiredSchema(schema), nil
+		}
+		return optionalSchema(schema), nil
+	case "priority":
+		priority, err := b.intValue(node)
+		if err != nil {
+			return nil, err
+		}
+		clone, base := cloneSchema(schema)
+		base.priority = priority
+		return clone, nil
+	case "messages":
+		object, err := b.object(node)
+		if err != nil {
+			return nil, err
+		}
+		messages := make(Messages, len(object.keys))
+		for _, rule := range object.keys {
+			if messages[rule], err = b.stringValue(object.values[rule]); err != nil {
+				return nil, err
+			}
+		}
+		clone, base := cloneSchema(schema)
+		base.messages = messages
+		return clone, nil
+	case "meta":
+		object, err := b.object(node)
+		if err != nil {
+			return nil, err
+		}
+		clone, base := cloneSchema(schema)
+		for _, name := range object.keys {
+			base.setMeta(name, plainValue(object.values[name]))
+		}
+		return clone, nil
+	case "when":
+		return b.when(schema, node)
+	case "rules":
+		return b.rules(schema, node)
+	}
+
+	switch s := schema.(type) {
+	case *AnySchema:
+		return b.anyRule(s, key, node)
+	case *StringSchema:
+		return b.stringRule(s, key, node)
+	case *NumberSchema:
+		return b.numberRule(s, key, node)
+	case *BoolSchema:
+		return b.boolRule(s, key, node)
+	case *ArraySchema:
+		return b.arrayRule(s, key, node)
+	case *ObjectSchema:
+		return b.objectRule(s, key, node)
+	case *DateSchema:
+		return b.dateRule(s, key, node)
+	case *DurationSchema:
+		return b.durationRule(s, key, node)
+	case *ByteSizeSchema:
+		return b.byteSizeRule(s, key, node)
+	default:
+		return b.alternativesRule(schema.(*AlternativesSchema), key, node)
+	}
+}
+
+// cloneSchema returns a clone of the schema and its baseSchema, so that the rules can be added to any type.
+func cloneSchema(schema Schema) (Schema, *baseSchema) {
+	switch s := schema.(type) {
+	case *AnySchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *StringSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *NumberSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *BoolSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *ArraySchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *ObjectSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *DateSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *DurationSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	case *ByteSizeSchema:
+		clone := s.clone()
+		return clone, &clone.baseSchema
+	default:
+		clone := schema.(*AlternativesSchema).clone()
+		return clone, &clone.baseSchema
+	}
+}
+
+// loadDefs builds the named schemas of the defs, the schemas are built before the root
+// so that the errors of the unused schemas are reported as well.
+func (b *loadBuilder) loadDefs(node *loadNode) error {
+	object, err := b.object(node)
+	if err != nil {
+		return err
+	}
+	b.defs = object.values
+	b.linked = make(map[string]Schema, len(object.keys))
+	for _, name := range object.keys {
+		if _, err := b.linkedSchema(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkedSchema returns the schema of the defs named name, the schema is built once and shared.
+func (b *loadBuilder) linkedSchema(name string) (Schema, error) {
+	if schema, ok := b.linked[name]; ok {
+		return schema, nil
+	}
+	// the placeholder allows the recursive links.
+	b.linked[name] = nil
+	schema, err := b.schema(b.defs[name])
+	if err != nil {
+		return nil, err
+	}
+	b.linked[name] = schema
+	return schema, nil
+}
+
+// link returns the Link schema of the named schema in the defs of the root document.
+func (b *loadBuilder) link(node *loadNode, object *loadObject) (Schema, error) {
+	nameNode, ok := object.values["name"]
+	if !ok {
+		return nil, b.errorf(node, "the link schema must have the \"name\" key")
+	}
+	name, err := b.stringValue(nameNode)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := b.defs[name]; !ok {
+		return nil, b.errorf(nameNode, "unknown schema %q in the defs", name)
+	}
+	if _, err := b.linkedSchema(name); err != nil {
+		return nil, err
+	}
+	linked := b.linked
+	schema := Link(name, func() Schema { return linked[name] })
+	if depthNode, ok := object.values["maxDepth"]; ok {
+		depth, err := b.intValue(depthNode)
+		if err != nil {
+			return nil, err
+		}
+		schema = schema.MaxDepth(depth)
+	}
+	if requiredNode, ok := object.values["required"]; ok {
+		required, err := b.boolValue(requiredNode)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			schema = schema.Required()
+		}
+	}
+	return schema, nil
+}
+
+// when add the conditions, the node is a condition or an array of conditions,
+// the value of `is` is a schema when it's an object, otherwise it's compared with the referenced value.
+func (b *loadBuilder) when(schema Schema, node *loadNode) (Schema, error) {
+	conditions := []*loadNode{node}
+	if items, ok := node.value.([]*loadNode); ok {
+		conditions = items
+	}
+	for _, item := range conditions {
+		object, err := b.object(item)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range object.keys {
+			if key != "ref" && key != "is" && key != "then" {
+				return nil, b.errorf(object.values[key], "unknown key %q of the condition", key)
+			}
+		}
+		for _, key := range []string{"ref", "is", "then"} {
+			if _, ok := object.values[key]; !ok {
+				return nil, b.errorf(item, "the condition must have the %q key", key)
+			}
+		}
+		refPath, err := b.stringValue(object.values["ref"])
+		if err != nil {
+			return nil, err
+		}
+		condition := plainValue(object.values["is"])
+		if _, ok := object.values["is"].value.(*loadObject); ok {
+			if condition, err = b.schema(object.values["is"]); err != nil {
+				return nil, err
+			}
+		}
+		then, err := b.schema(object.values["then"])
+		if err != nil {
+			return nil, err
+		}
+
+		clone, base := cloneSchema(schema)
+		base.appendRule(func(ctx *Context) { base.when(ctx, refPath, condition, then) })
+		base.addRefs(refPath)
+		base.addRefs(schemaRefs(then)...)
+		base.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+		schema = clone
+	}
+	return schema, nil
+}
+
+// rules add the custom rules registered to the loader in order.
+func (b *loadBuilder) rules(schema Schema, node *loadNode) (Schema, error) {
+	items, err := b.array(node)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		var name string
+		var args interface{}
+		if object, ok := item.value.(*loadObject); ok {
+			for _, key := range object.keys {
+				if key != "name" && key != "args" {
+					return nil, b.errorf(object.values[key], "unknown key %q of the rule", key)
+				}
+			}
+			nameNode, ok := object.values["name"]
+			if !ok {
+				return nil, b.errorf(item, "the rule must have the \"name\" key")
+			}
+			if name, err = b.stringValue(nameNode); err != nil {
+				return nil, err
+			}
+			if argsNode, ok := object.values["args"]; ok {
+				args = plainValue(argsNode)
+			}
+		} else if name, err = b.stringValue(item); err != nil {
+			return nil, b.errorf(item, "the rule must be a name or an object")
+		}
+
+		newRule, ok := b.loader.rule(name)
+		if !ok {
+			return nil, b.errorf(item, "unknown rule %q", name)
+		}
+		rule, err := newRule(args)
+		if err != nil {
+			return nil, b.errorf(item, "rule %q: %s", name, err)
+		}
+		clone, base := cloneSchema(schema)
+		base.appendRule(rule)
+		base.descs[len(base.descs)-1] = ruleDesc{name: name, args: map[string]interface{}{"args": args}}
+		schema = clone
+	}
+	return schema, nil
+}
+
+func (b *loadBuilder) anyRule(schema *AnySchema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "default":
+		return schema.Default(plainValue(node)), nil
+	case "equal":
+		return schema.Equal(plainValue(node)), nil
+	default:
+		items, err := b.array(node)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(items))
+		for i, item := range items {
+			values[i] = plainValue(item)
+		}
+		return schema.Valid(values...), nil
+	}
+}
+
+func (b *loadBuilder) stringRule(schema *StringSchema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "default", "equal", "regex", "format":
+		value, err := b.stringValue(node)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "default":
+			return schema.Default(value), nil
+		case "equal":
+			return schema.Equal(value), nil
+		case "format":
+			formatted, ok := stringFormat(schema, value)
+			if !ok {
+				return nil, b.errorf(node, "unknown format %q", value)
+			}
+			return formatted, nil
+		}
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, b.errorf(node, "invalid regex: %s", err)
+		}
+		return schema.Regex(value), nil
+	case "min", "max", "length":
+		limit, err := b.intValue(node)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "min":
+			return schema.Min(limit), nil
+		case "max":
+			return schema.Max(limit), nil
+		}
+		return schema.Length(limit), nil
+	case "valid":
+		items, err := b.array(node)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(items))
+		for i, item := range items {
+			if values[i], err = b.stringValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return schema.Valid(values...), nil
+	}
+
+	flag, err := b.boolValue(node)
+	if err != nil || !flag {
+		return schema, err
+	}
+	switch key {
+	case "trim":
+		return schema.Trim(), nil
+	case "lowercase":
+		return schema.Lowercase(), nil
+	case "uppercase":
+		return schema.Uppercase(), nil
+	case "alphanum":
+		return schema.Alphanum(), nil
+	default:
+		return schema.Token(), nil
+	}
+}
+
+func (b *loadBuilder) numberRule(schema *NumberSchema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "default", "equal", "min", "max", "greater", "less":
+		value, err := b.numberValue(node)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "default":
+			return schema.Default(value), nil
+		case "equal":
+			return schema.Equal(value), nil
+		case "min":
+			return schema.Min(value), nil
+		case "max":
+			return schema.Max(value), nil
+		case "greater":
+			return schema.Greater(value), nil
+		}
+		return schema.Less(value), nil
+	case "valid":
+		items, err := b.array(node)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]float64, len(items))
+		for i, item := range items {
+			if values[i], err = b.numberValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return schema.Valid(values...), nil
+	}
+
+	flag, err := b.boolValue(node)
+	if err != nil || !flag {
+		return schema, err
+	}
+	switch key {
+	case "parseString":
+		return schema.ParseString(), nil
+	case "ceil":
+		return schema.Ceil(), nil
+	case "floor":
+		return schema.Floor(), nil
+	case "round":
+		return schema.Round(), nil
+	default:
+		return schema.Integer(), nil
+	}
+}
+
+func (b *loadBuilder) boolRule(schema *BoolSchema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "default", "equal":
+		value, err := b.boolValue(node)
+		if err != nil {
+			return nil, err
+		}
+		if key == "default" {
+			return schema.Default(value), nil
+		}
+		return schema.Equal(value), nil
+	case "valid":
+		items, err := b.array(node)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]bool, len(items))
+		for i, item := range items {
+			if values[i], err = b.boolValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return schema.Valid(values...), nil
+	}
+
+	items, err := b.array(node)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		values[i] = plainValue(item)
+	}
+	if key == "truthy" {
+		return schema.Truthy(values...), nil
+	}
+	return schema.Falsy(values...), nil
+}
+
+func (b *loadBuilder) arrayRule(schema *ArraySchema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "default":
+		if _, err := b.array(node); err != nil {
+			return nil, err
+		}
+		return schema.Default(plainValue(node)), nil
+	case "items":
+		items := []*loadNode{node}
+		if array, ok := node.value.([]*loadNode); ok {
+			items = array
+		}
+		schemas := make([]Schema, len(items))
+		for i, item := range items {
+			var err error
+			if schemas[i], err = b.schema(item); err != nil {
+				return nil, err
+			}
+		}
+		return schema.Items(schemas...), nil
+	}
+
+	limit, err := b.intValue(node)
+	if err != nil {
+		return nil, err
+	}
+	switch key {
+	case "min":
+		return schema.Min(limit), nil
+	case "max":
+		return schema.Max(limit), nil
+	default:
+		return schema.Length(limit), nil
+	}
+}
+
+func (b *loadBuilder) objectRule(schema *ObjectSchema, key string, node *loadNode) (Schema, error) {
+	switch key {
+	case "default":
+		if _, err := b.object(node); err != nil {
+			return nil, err
+		}
+		return schema.Default(plainValue(node).(map[string]interface{})), nil
+	case "keys":
+		object, err := b.object(node)
+		if err != nil {
+			return nil, err
+		}
+		children := make(K, len(object.keys))
+		for _, name := range object.keys {
+			if children[name], err = b.schema(object.values[name]); err != nil {
+				return nil, err
+			}
+		}
+		return schema.Keys(children), nil
+	case "discriminator":
+		return b.discriminator(schema, node)
+	case "with", "without":
+		keys, err := b.stringsValue(node)
+		if err != nil {
+			return nil, err
+		}
+		if key == "with" {
+			return schema.With(keys...), nil
+		}
+		return schema.Without(keys...), nil
+	}
+
+	object, err := b.object(node)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range object.keys {
+		if name != "key" && name != "peers" {
+			return nil, b.errorf(object.values[name], "unknown key %q of withoutPeers", name)
+		}
+	}
+	keyNode, ok := object.values["key"]
+	if !ok {
+		return nil, b.errorf(node, "withoutPeers must have the \"key\" key")
+	}
+	withoutKey, err := b.stringValue(keyNode)
+	if err != nil {
+		return nil, err
+	}
+	var peers []string
+	if peersNode, ok := object.values["peers"]; ok {
+		if peers, err = b.stringsValue(peersNode); err != nil {
+			return nil, err
+		}
+	}
+	return schema.WithoutPeers(withoutKey, peers...), nil
+}
+
+// discriminator add the Discriminator, the node is an object with the key and the keys of the cases,
+// such as `{"key": "type", "cases": {"click": {"x": {"type": "number"}}}}`.
+func (b *loadBuilder) discriminator(schema *ObjectSchema, node *loadNode) (Schema, error) {
+	object, err := b.object(node)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range object.keys {
+		if name != "key" && name != "cases" {
+			return nil, b.errorf(object.values[name], "unknown key %q of discriminator", name)
+		}
+	}
+	for _, name := range []string{"key", "cases"} {
+		if _, ok := object.values[name]; !ok {
+			return nil, b.errorf(node, "discriminator must have the %q key", name)
+		}
+	}
+	key, err := b.stringValue(object.values["key"])
+	if err != nil {
+		return nil, err
+	}
+	casesObject, err := b.object(object.values["cases"])
+	if err != nil {
+		return nil, err
+	}
+	cases := make(map[string]K, len(casesObject.keys))
+	for _, value := range casesObject.keys {
+		keysObject, err := b.object(casesObject.values[value])
+		if err != nil {
+			return nil, err
+		}
+		children := make(K, len(keysObject.keys))
+		for _, name := range keysObject.keys {
+			if children[name], err = b.schema(keysObject.values[name]); err != nil {
+				return nil, err
+			}
+		}
+		cases[value] = children
+	}
+	return schema.Discriminator(key, cases), nil
+}
+
+func (b *loadBuilder) alternativesRule(schema *AlternativesSchema, key string, node *loadNode) (Schema, error) {
+	if key == "default" {
+		return schema.Default(plainValue(node)), nil
+	}
+	items, err := b.array(node)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]Schema, len(items))
+	for i, item := range items {
+		if schemas[i], err = b.schema(item); err != nil {
+			return nil, err
+		}
+	}
+	switch key {
+	case "try":
+		return schema.Try(schemas...), nil
+	case "oneOf":
+		return schema.OneOf(schemas...), nil
+	default:
+		return schema.AllOf(schemas...), nil
+	}
+}
+
+func (b *loadBuilder) dateRule(schema *DateSchema, key string, node *loadNode) (Schema, error) {
+	if key == "layouts" {
+		layouts, err := b.stringsValue(node)
+		if err != nil {
+			return nil, err
+		}
+		return schema.Layouts(layouts...), nil
+	}
+	value, err := b.stringValue(node)
+	if err != nil {
+		return nil, err
+	}
+	switch key {
+	case "default":
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, b.errorf(node, "must be a RFC 3339 date")
+		}
+		return schema.Default(date), nil
+	case "timestamp":
+		if value != DateUnix && value != DateUnixMilli {
+			return nil, b.errorf(node, "unknown timestamp unit %q", value)
+		}
+		return schema.Timestamp(value), nil
+	case "location":
+		location, err := time.LoadLocation(value)
+		if err != nil {
+			return nil, b.errorf(node, "unknown location %q", value)
+		}
+		return schema.In(location), nil
+	case "output":
+		return schema.Output(value), nil
+	}
+
+	var limit interface{} = value
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		limit = date
+	} else if duration, err := time.ParseDuration(value); err == nil {
+		limit = duration
+	}
+	switch key {
+	case "min":
+		return schema.Min(limit), nil
+	case "max":
+		return schema.Max(limit), nil
+	case "before":
+		return schema.Before(limit), nil
+	default:
+		return schema.After(limit), nil
+	}
+}
+
+func (b *loadBuilder) durationRule(schema *DurationSchema, key string, node *loadNode) (Schema, error) {
+	if key == "output" {
+		output, err := b.stringValue(node)
+		if err != nil {
+			return nil, err
+		}
+		if output != DurationString && output != DurationSeconds && output != DurationValue {
+			return nil, b.errorf(node, "unknown output %q", output)
+		}
+		return schema.Output(output), nil
+	}
+	value, err := b.durationValue(node)
+	if err != nil {
+		return nil, err
+	}
+	switch key {
+	case "default":
+		return schema.Default(value), nil
+	case "min":
+		return schema.Min(value), nil
+	default:
+		return schema.Max(value), nil
+	}
+}
+
+func (b *loadBuilder) byteSizeRule(schema *ByteSizeSchema, key string, node *loadNode) (Schema, error) {
+	if key == "output" {
+		output, err := b.stringValue(node)
+		if err != nil {
+			return nil, err
+		}
+		if output != ByteSizeBytes && output != ByteSizeString {
+			return nil, b.errorf(node, "unknown output %q", output)
+		}
+		return schema.Output(output), nil
+	}
+	value, err := b.byteSizeValue(node)
+	if err != nil {
+		return nil, err
+	}
+	switch key {
+	case "default":
+		return schema.Default(value), nil
+	case "min":
+		return schema.Min(value), nil
+	default:
+		return schema.Max(value), nil
+	}
+}
+
+func (b *loadBuilder) object(node *loadNode) (*loadObject, error) {
+	object, ok := node.value.(*loadObject)
+	if !ok {
+		return nil, b.errorf(node, "must be an object")
+	}
+	return object, nil
+}
+
+func (b *loadBuilder) array(node *loadNode) ([]*loadNode, error) {
+	items, ok := node.value.([]*loadNode)
+	if !ok {
+		return nil, b.errorf(node, "must be an array")
+	}
+	return items, nil
+}
+
+func (b *loadBuilder) stringValue(node *loadNode) (string, error) {
+	value, ok := node.value.(string)
+	if !ok {
+		return "", b.errorf(node, "must be a string")
+	}
+	return value, nil
+}
+
+func (b *loadBuilder) stringsValue(node *loadNode) ([]string, error) {
+	items, err := b.array(node)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		if values[i], err = b.stringValue(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (b *loadBuilder) boolValue(node *loadNode) (bool, error) {
+	value, ok := node.value.(bool)
+	if !ok {
+		return false, b.errorf(node, "must be a boolean")
+	}
+	return value, nil
+}
+
+func (b *loadBuilder) numberValue(node *loadNode) (float64, error) {
+	value, ok := loadNumber(node.value)
+	if !ok {
+		return 0, b.errorf(node, "must be a number")
+	}
+	return value, nil
+}
+
+func (b *loadBuilder) intValue(node *loadNode) (int, error) {
+	value, ok := loadNumber(node.value)
+	if !ok || value < 0 || value != float64(int(value)) {
+		return 0, b.errorf(node, "must be a non-negative integer")
+	}
+	return int(value), nil
+}
+
+func (b *loadBuilder) durationValue(node *loadNode) (time.Duration, error) {
+	if value, ok := node.value.(string); ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, b.errorf(node, "invalid duration %q", value)
+		}
+		return duration, nil
+	}
+	seconds, ok := loadNumber(node.value)
+	if !ok || math.IsNaN(seconds) || math.Abs(seconds) > math.MaxInt64/float64(time.Second) {
+		return 0, b.errorf(node, "must be a duration or a number of seconds")
+	}
+	return time.Duration(math.Round(seconds * float64(time.Second))), nil
+}
+
+func (b *loadBuilder) byteSizeValue(node *loadNode) (int64, error) {
+	if value, ok := node.value.(string); ok {
+		size, ok := parseByteSize(value)
+		if !ok {
+			return 0, b.errorf(node, "invalid size %q", value)
+		}
+		return size, nil
+	}
+	value, ok := loadNumber(node.value)
+	if !ok || value < 0 || value >= math.MaxInt64 || value != math.Trunc(value) {
+		return 0, b.errorf(node, "must be a size or a number of bytes")
+	}
+	return int64(value), nil
+}
+
+// loadNumber accepts the numbers of encoding/json and the integers decoded by the yaml packages.
+func loadNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	}
+	return toFloat64(value)
+}
+
+// plainValue returns the value of the node as encoding/json decodes it, the numbers are float64.
+func plainValue(node *loadNode) interface{} {
+	switch v := node.value.(type) {
+	case *loadObject:
+		value := make(map[string]interface{}, len(v.keys))
+		for _, key := range v.keys {
+			value[key] = plainValue(v.values[key])
+		}
+		return value
+	case []*loadNode:
+		value := make([]interface{}, len(v))
+		for i, item := range v {
+			value[i] = plainValue(item)
+		}
+		return value
+	}
+	if number, ok := loadNumber(node.value); ok {
+		return number
+	}
+	return node.value
+}
@@ -0,0 +1,264 @@
+package jio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const loaderTestDocument = `{
+  "type": "object",
+  "keys": {
+    "name": {"type": "string", "required": true, "min": 3, "max": 18, "trim": true, "rules": ["slug"]},
+    "role": {"type": "string", "valid": ["admin", "guest"], "default": "guest"},
+    "age": {
+      "type": "number",
+      "integer": true,
+      "min": 0,
+      "when": {"ref": "role", "is": "admin", "then": {"type": "number", "min": 18}}
+    },
+    "tags": {"type": "array", "max": 2, "items": {"type": "string", "rules": [{"name": "prefix", "args": "#"}]}},
+    "active": {"type": "bool", "truthy": ["yes"]}
+  }
+}`
+
+func newTestLoader() *Loader {
+	loader := NewLoader()
+	loader.Register("slug", func(args interface{}) (func(*Context), error) {
+		return func(ctx *Context) {
+			if strings.Contains(ctx.Value.(string), " ") {
+				ctx.Abort(fmt.Errorf("%s must be a slug", ctx.FieldPath()))
+			}
+		}, nil
+	})
+	loader.Register("prefix", func(args interface{}) (func(*Context), error) {
+		prefix, ok := args.(string)
+		if !ok {
+			return nil, errors.New("args must be a string")
+		}
+		return func(ctx *Context) {
+			if !strings.HasPrefix(ctx.Value.(string), prefix) {
+				ctx.Abort(fmt.Errorf("%s must start with %s", ctx.FieldPath(), prefix))
+			}
+		}, nil
+	})
+	return loader
+}
+
+func TestLoader_Load(t *testing.T) {
+	schema, err := newTestLoader().Load([]byte(loaderTestDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{"name": "  jio-go ", "age": 20.0, "tags": []interface{}{"#go"}, "active": "yes"}
+	ctx := NewContext(data)
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Fatal(ctx.Err)
+	}
+	if data["name"] != "jio-go" || data["role"] != "guest" || data["active"] != true {
+		t.Error("should apply the conversions and the defaults")
+	}
+
+	for _, data := range []map[string]interface{}{
+		{"name": "jio go"},
+		{"name": "jio", "tags": []interface{}{"go"}},
+		{"name": "jio", "role": "root"},
+		{"name": "jio", "role": "admin", "age": 16.0},
+		{"name": "jio", "age": 1.5},
+		{"name": "jio", "tags": []interface{}{"#a", "#b", "#c"}},
+	} {
+		ctx := NewContext(data)
+		schema.Validate(ctx)
+		if ctx.Err == nil {
+			t.Errorf("%v should be invalid", data)
+		}
+	}
+}
+
+func TestLoader_LoadMap(t *testing.T) {
+	schema, err := NewLoader().LoadMap(map[string]interface{}{
+		"type": "object",
+		"keys": map[interface{}]interface{}{
+			"count": map[string]interface{}{"type": "number", "max": 10, "required": true},
+		},
+		"with":         []interface{}{"count"},
+		"without":      []interface{}{"sum"},
+		"withoutPeers": map[string]interface{}{"key": "count", "peers": []interface{}{"total"}},
+		"priority":     1,
+		"meta":         map[string]interface{}{"title": "counter"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Priority() != 1 || Describe(schema).Metadata["title"] != "counter" {
+		t.Error("should set the priority and the metadata")
+	}
+	ctx := NewContext(map[string]interface{}{"count": 11.0})
+	schema.Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should check the max")
+	}
+	ctx = NewContext(map[string]interface{}{"count": 1.0, "sum": 1.0})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "object.without" {
+		t.Error("should forbid the keys", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"count": 1.0, "total": 1.0})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "object.withoutPeers" {
+		t.Error("should forbid the peers", ctx.Err)
+	}
+}
+
+func TestLoader_Link(t *testing.T) {
+	schema, err := NewLoader().Load([]byte(`{
+  "defs": {
+    "comment": {
+      "type": "object",
+      "keys": {
+        "text": {"type": "string", "required": true},
+        "replies": {"type": "array", "items": {"type": "link", "name": "comment", "maxDepth": 2}}
+      }
+    }
+  },
+  "type": "link",
+  "name": "comment",
+  "required": true
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := map[string]interface{}{"text": "b"}
+	ctx := NewContext(map[string]interface{}{"text": "a", "replies": []interface{}{reply}})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should validate with the linked schema", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"text": "a", "replies": []interface{}{map[string]interface{}{}}})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" || err.Path != "replies.0.text" {
+		t.Error("should validate the nested links", ctx.Err)
+	}
+	ctx = NewContext(map[string]interface{}{"text": "a", "replies": []interface{}{
+		map[string]interface{}{"text": "b", "replies": []interface{}{reply}},
+	}})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "link.depth" {
+		t.Error("should set the max depth", ctx.Err)
+	}
+	ctx = NewContext(nil)
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" {
+		t.Error("should set the required", ctx.Err)
+	}
+}
+
+func TestLoader_Date(t *testing.T) {
+	schema, err := NewLoader().Load([]byte(`{
+  "type": "object",
+  "keys": {
+    "start": {"type": "date", "required": true, "layouts": ["2006-01-02"], "location": "UTC", "min": "2020-01-01T00:00:00Z", "output": "unix"},
+    "end": {"type": "date", "timestamp": "unixMilli", "after": "start", "max": "8760h"}
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{"start": "2020-01-02", "end": 1577966400000.0}
+	ctx := NewContext(data)
+	schema.Validate(ctx)
+	if ctx.Err != nil || data["start"] != 1577923200.0 || data["end"] != "2020-01-02T12:00:00Z" {
+		t.Error("should parse and output the dates", ctx.Err, data)
+	}
+	for _, data := range []map[string]interface{}{
+		{"start": "2019-12-31"},
+		{"start": "2020-01-02", "end": "2020-01-01T00:00:00Z"},
+		{"start": "2020-01-02", "end": time.Now().Add(10000 * time.Hour).Format(time.RFC3339)},
+	} {
+		ctx := NewContext(data)
+		schema.Validate(ctx)
+		if ctx.Err == nil {
+			t.Errorf("%v should be invalid", data)
+		}
+	}
+}
+
+func TestLoader_DurationAndByteSize(t *testing.T) {
+	schema, err := NewLoader().Load([]byte(`{
+  "type": "object",
+  "keys": {
+    "timeout": {"type": "duration", "default": 30, "min": "1s", "max": "1h", "output": "seconds"},
+    "memory": {"type": "byteSize", "required": true, "min": 1024, "max": "1GiB", "output": "string"}
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{"memory": "1048576"}
+	ctx := NewContext(data)
+	schema.Validate(ctx)
+	if ctx.Err != nil || data["timeout"] != 30.0 || data["memory"] != "1MiB" {
+		t.Error("should parse and output the values", ctx.Err, data)
+	}
+	for _, data := range []map[string]interface{}{
+		{"memory": "512B"},
+		{"memory": "2GiB"},
+		{"memory": "1MiB", "timeout": "100ms"},
+		{"memory": "1MiB", "timeout": "2h"},
+	} {
+		ctx := NewContext(data)
+		schema.Validate(ctx)
+		if ctx.Err == nil {
+			t.Errorf("%v should be invalid", data)
+		}
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	loader := newTestLoader()
+	cases := []struct {
+		document string
+		err      string
+	}{
+		{`{"type": "string",
+  "min": "3"}`, "jio: #/min (line 2, column 10): must be a non-negative integer"},
+		{`{"type": "object", "keys": {"name": {"type": "text"}}}`, "jio: #/keys/name/type (line 1, column 46): unknown type \"text\""},
+		{`{"type": "number", "trim": true}`, "jio: #/trim (line 1, column 28): unknown key \"trim\" of the number schema"},
+		{`{"type": "string", "regex": "["}`, "jio: #/regex (line 1, column 29): invalid regex: error parsing regexp: missing closing ]: `[`"},
+		{`{"type": "string", "rules": ["upper"]}`, "jio: #/rules/0 (line 1, column 30): unknown rule \"upper\""},
+		{`{"rules": [{"name": "prefix", "args": 1}]}`, "jio: #/rules/0 (line 1, column 12): rule \"prefix\": args must be a string"},
+		{`{"when": {"ref": "a", "then": {}}}`, "jio: #/when (line 1, column 10): the condition must have the \"is\" key"},
+		{`{"type": "string", "min": 1,}`, "jio: # (line 1, column 29): invalid character ',' looking for beginning of value"},
+		{`{"type": "string", "type": "any"}`, "jio: # (line 1, column 20): duplicate key \"type\""},
+		{`{"type": "array"`, "jio: # (line 1, column 17): unexpected end of JSON input"},
+		{`{} {}`, "jio: # (line 1, column 4): unexpected data after the document"},
+		{`{"type": "date", "timestamp": "nano"}`, "jio: #/timestamp (line 1, column 31): unknown timestamp unit \"nano\""},
+		{`{"type": "date", "location": "Mars/Base"}`, "jio: #/location (line 1, column 30): unknown location \"Mars/Base\""},
+		{`{"type": "duration", "min": "1 hour"}`, "jio: #/min (line 1, column 29): invalid duration \"1 hour\""},
+		{`{"type": "duration", "output": "hours"}`, "jio: #/output (line 1, column 32): unknown output \"hours\""},
+		{`{"type": "byteSize", "max": 1.5}`, "jio: #/max (line 1, column 29): must be a size or a number of bytes"},
+		{`{"type": "link", "name": "user"}`, "jio: #/name (line 1, column 26): unknown schema \"user\" in the defs"},
+		{`{"defs": {"user": {"type": "link"}}}`, "jio: #/defs/user (line 1, column 19): the link schema must have the \"name\" key"},
+		{`{"type": "array", "items": {"defs": {}}}`, "jio: #/items/defs (line 1, column 37): unknown key \"defs\" of the any schema"},
+		{`{"defs": {"a": {}}, "type": "link", "name": "a", "default": 1}`, "jio: #/default (line 1, column 61): unknown key \"default\" of the link schema"},
+	}
+	for _, c := range cases {
+		_, err := loader.Load([]byte(c.document))
+		if err == nil || err.Error() != c.err {
+			t.Errorf("%s: got %v", c.document, err)
+		}
+		var loadErr *LoadError
+		if !errors.As(err, &loadErr) {
+			t.Error("should return a LoadError")
+		}
+	}
+
+	_, err := loader.LoadMap(map[string]interface{}{"type": "array", "items": []interface{}{"string"}})
+	if err == nil || err.Error() != "jio: #/items/0: must be an object" {
+		t.Error("should report the json pointer", err)
+	}
+}
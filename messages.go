@@ -0,0 +1,343 @@
+package jio
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Messages is a message catalog keyed by rule code, such as `string.min`.
+// The templates can use `{{label}}` for the field path, `{{value}}` for the value,
+// and the rule arguments such as `{{limit}}` as placeholders.
+type Messages map[string]string
+
+var defaultMessages = Messages{
+	"any.custom":           "{{error}}",
+	"any.required":         "field `{{label}}` is required",
+	"any.equal":            "field `{{label}}` value {{value}} is not {{expected}}",
+	"any.valid":            "field `{{label}}` value {{value}} is not in {{valids}}",
+	"any.decode":           "field `{{label}}` value {{value}} cannot be decoded into {{type}}",
+	"string.base":          "field `{{label}}` value {{value}} is not string",
+	"string.check":         "field `{{label}}` value {{value}} {{error}}",
+	"string.equal":         "field `{{label}}` value {{value}} is not {{expected}}",
+	"string.valid":         "field `{{label}}` value {{value}} not in {{valids}}",
+	"string.min":           "field `{{label}}` value {{value}} length less than {{limit}}",
+	"string.max":           "field `{{label}}` value {{value}} length exceeded {{limit}}",
+	"string.length":        "field `{{label}}` value {{value}} length not equal to {{limit}}",
+	"string.regex":         "field `{{label}}` value {{value}} not match with {{regex}}",
+	"string.alphanum":      "field `{{label}}` value {{value}} must only contain alpha-numeric characters",
+	"string.token":         "field `{{label}}` value {{value}} must only contain alpha-numeric and underscore characters",
+	"string.email":         "field `{{label}}` value {{value}} is not a valid email",
+	"string.uuid":          "field `{{label}}` value {{value}} is not a valid uuid",
+	"string.uri":           "field `{{label}}` value {{value}} is not a valid uri",
+	"string.hostname":      "field `{{label}}` value {{value}} is not a valid hostname",
+	"string.ip":            "field `{{label}}` value {{value}} is not a valid ip address",
+	"string.cidr":          "field `{{label}}` value {{value}} is not a valid cidr",
+	"string.hex":           "field `{{label}}` value {{value}} must only contain hexadecimal characters",
+	"string.base64":        "field `{{label}}` value {{value}} is not a valid base64 string",
+	"string.country":       "field `{{label}}` value {{value}} is not a valid country code",
+	"number.base":          "field `{{label}}` value {{value}} is not number",
+	"number.check":         "field `{{label}}` value {{value}} {{error}}",
+	"number.equal":         "field `{{label}}` value {{value}} is not {{expected}}",
+	"number.valid":         "field `{{label}}` value {{value}} not in {{valids}}",
+	"number.min":           "field `{{label}}` value {{value}} less than {{limit}}",
+	"number.max":           "field `{{label}}` value {{value}} exceeded {{limit}}",
+	"number.greater":       "field `{{label}}` value {{value}} must be greater than {{limit}}",
+	"number.less":          "field `{{label}}` value {{value}} must be less than {{limit}}",
+	"number.integer":       "field `{{label}}` value {{value}} not integer",
+	"number.parse":         "field `{{label}}` value {{value}} convert to number failed",
+	"bool.base":            "field `{{label}}` value {{value}} is not boolean",
+	"bool.equal":           "field `{{label}}` value {{value}} is not {{expected}}",
+	"bool.check":           "field `{{label}}` value {{value}} {{error}}",
+	"bool.valid":           "field `{{label}}` value {{value}} not in {{valids}}",
+	"object.base":          "field `{{label}}` value {{value}} is not object",
+	"object.with":          "field `{{label}}` not contains {{peer}}",
+	"object.without":       "field `{{label}}` contains {{keys}}",
+	"object.withoutPeers":  "field `{{label}}` contains {{peers}} with {{key}}",
+	"object.unknown":       "field `{{label}}` is not allowed",
+	"object.discriminator": "field `{{label}}` value {{value}} is unknown, expected one of {{valids}}",
+	"array.base":           "field `{{label}}` value {{value}} is not array",
+	"array.check":          "field `{{label}}` value {{value}} {{error}}",
+	"array.min":            "field `{{label}}` value {{value}} length less than {{limit}}",
+	"array.max":            "field `{{label}}` value {{value}} length exceeded {{limit}}",
+	"array.length":         "field `{{label}}` value {{value}} length not equal to {{limit}}",
+	"alternatives.one":     "field `{{label}}` value {{value}} matches more than one schema",
+	"link.depth":           "field `{{label}}` is nested deeper than {{limit}}",
+	"date.base":            "field `{{label}}` value {{value}} is not a valid date",
+	"date.check":           "field `{{label}}` value {{value}} {{error}}",
+	"date.min":             "field `{{label}}` value {{value}} is earlier than {{limit}}",
+	"date.max":             "field `{{label}}` value {{value}} is later than {{limit}}",
+	"date.before":          "field `{{label}}` value {{value}} must be before {{limit}}",
+	"date.after":           "field `{{label}}` value {{value}} must be after {{limit}}",
+	"duration.base":        "field `{{label}}` value {{value}} is not a valid duration",
+	"duration.parse":       "field `{{label}}` value {{value}} convert to duration failed",
+	"duration.check":       "field `{{label}}` value {{value}} {{error}}",
+	"duration.min":         "field `{{label}}` value {{value}} shorter than {{limit}}",
+	"duration.max":         "field `{{label}}` value {{value}} longer than {{limit}}",
+	"byteSize.base":        "field `{{label}}` value {{value}} is not a valid size",
+	"byteSize.parse":       "field `{{label}}` value {{value}} convert to size failed",
+	"byteSize.check":       "field `{{label}}` value {{value}} {{error}}",
+	"byteSize.min":         "field `{{label}}` value {{value}} smaller than {{limit}}",
+	"byteSize.max":         "field `{{label}}` value {{value}} larger than {{limit}}",
+}
+
+var chineseMessages = Messages{
+	"any.custom":           "{{error}}",
+	"any.required":         "字段 `{{label}}` 不能为空",
+	"any.equal":            "字段 `{{label}}` 的值 {{value}} 不等于 {{expected}}",
+	"any.valid":            "字段 `{{label}}` 的值 {{value}} 不在 {{valids}} 中",
+	"any.decode":           "字段 `{{label}}` 的值 {{value}} 无法解码为 {{type}}",
+	"string.base":          "字段 `{{label}}` 的值 {{value}} 不是字符串",
+	"string.check":         "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"string.equal":         "字段 `{{label}}` 的值 {{value}} 不等于 {{expected}}",
+	"string.valid":         "字段 `{{label}}` 的值 {{value}} 不在 {{valids}} 中",
+	"string.min":           "字段 `{{label}}` 的值 {{value}} 长度小于 {{limit}}",
+	"string.max":           "字段 `{{label}}` 的值 {{value}} 长度超过 {{limit}}",
+	"string.length":        "字段 `{{label}}` 的值 {{value}} 长度不等于 {{limit}}",
+	"string.regex":         "字段 `{{label}}` 的值 {{value}} 不匹配 {{regex}}",
+	"string.alphanum":      "字段 `{{label}}` 的值 {{value}} 只能包含字母和数字",
+	"string.token":         "字段 `{{label}}` 的值 {{value}} 只能包含字母、数字和下划线",
+	"string.email":         "字段 `{{label}}` 的值 {{value}} 不是有效的邮箱地址",
+	"string.uuid":          "字段 `{{label}}` 的值 {{value}} 不是有效的 UUID",
+	"string.uri":           "字段 `{{label}}` 的值 {{value}} 不是有效的 URI",
+	"string.hostname":      "字段 `{{label}}` 的值 {{value}} 不是有效的主机名",
+	"string.ip":            "字段 `{{label}}` 的值 {{value}} 不是有效的 IP 地址",
+	"string.cidr":          "字段 `{{label}}` 的值 {{value}} 不是有效的 CIDR",
+	"string.hex":           "字段 `{{label}}` 的值 {{value}} 只能包含十六进制字符",
+	"string.base64":        "字段 `{{label}}` 的值 {{value}} 不是有效的 Base64 字符串",
+	"string.country":       "字段 `{{label}}` 的值 {{value}} 不是有效的国家代码",
+	"number.base":          "字段 `{{label}}` 的值 {{value}} 不是数字",
+	"number.check":         "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"number.equal":         "字段 `{{label}}` 的值 {{value}} 不等于 {{expected}}",
+	"number.valid":         "字段 `{{label}}` 的值 {{value}} 不在 {{valids}} 中",
+	"number.min":           "字段 `{{label}}` 的值 {{value}} 小于 {{limit}}",
+	"number.max":           "字段 `{{label}}` 的值 {{value}} 大于 {{limit}}",
+	"number.greater":       "字段 `{{label}}` 的值 {{value}} 必须大于 {{limit}}",
+	"number.less":          "字段 `{{label}}` 的值 {{value}} 必须小于 {{limit}}",
+	"number.integer":       "字段 `{{label}}` 的值 {{value}} 不是整数",
+	"number.parse":         "字段 `{{label}}` 的值 {{value}} 无法转换为数字",
+	"bool.base":            "字段 `{{label}}` 的值 {{value}} 不是布尔值",
+	"bool.equal":           "字段 `{{label}}` 的值 {{value}} 不等于 {{expected}}",
+	"bool.check":           "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"bool.valid":           "字段 `{{label}}` 的值 {{value}} 不在 {{valids}} 中",
+	"object.base":          "字段 `{{label}}` 的值 {{value}} 不是对象",
+	"object.with":          "字段 `{{label}}` 缺少 {{peer}}",
+	"object.without":       "字段 `{{label}}` 不能包含 {{keys}}",
+	"object.withoutPeers":  "字段 `{{label}}` 包含 {{key}} 时不能包含 {{peers}}",
+	"object.unknown":       "字段 `{{label}}` 不允许出现",
+	"object.discriminator": "字段 `{{label}}` 的值 {{value}} 未知，应为 {{valids}} 之一",
+	"array.base":           "字段 `{{label}}` 的值 {{value}} 不是数组",
+	"array.check":          "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"array.min":            "字段 `{{label}}` 的值 {{value}} 长度小于 {{limit}}",
+	"array.max":            "字段 `{{label}}` 的值 {{value}} 长度超过 {{limit}}",
+	"array.length":         "字段 `{{label}}` 的值 {{value}} 长度不等于 {{limit}}",
+	"alternatives.one":     "字段 `{{label}}` 的值 {{value}} 匹配了多个 Schema",
+	"link.depth":           "字段 `{{label}}` 的嵌套深度超过了 {{limit}}",
+	"date.base":            "字段 `{{label}}` 的值 {{value}} 不是有效的日期",
+	"date.check":           "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"date.min":             "字段 `{{label}}` 的值 {{value}} 早于 {{limit}}",
+	"date.max":             "字段 `{{label}}` 的值 {{value}} 晚于 {{limit}}",
+	"date.before":          "字段 `{{label}}` 的值 {{value}} 必须早于 {{limit}}",
+	"date.after":           "字段 `{{label}}` 的值 {{value}} 必须晚于 {{limit}}",
+	"duration.base":        "字段 `{{label}}` 的值 {{value}} 不是有效的时长",
+	"duration.parse":       "字段 `{{label}}` 的值 {{value}} 无法转换为时长",
+	"duration.check":       "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"duration.min":         "字段 `{{label}}` 的值 {{value}} 短于 {{limit}}",
+	"duration.max":         "字段 `{{label}}` 的值 {{value}} 长于 {{limit}}",
+	"byteSize.base":        "字段 `{{label}}` 的值 {{value}} 不是有效的大小",
+	"byteSize.parse":       "字段 `{{label}}` 的值 {{value}} 无法转换为大小",
+	"byteSize.check":       "字段 `{{label}}` 的值 {{value}} {{error}}",
+	"byteSize.min":         "字段 `{{label}}` 的值 {{value}} 小于 {{limit}}",
+	"byteSize.max":         "字段 `{{label}}` 的值 {{value}} 大于 {{limit}}",
+}
+
+var germanMessages = Messages{
+	"any.custom":           "{{error}}",
+	"any.required":         "Feld `{{label}}` ist erforderlich",
+	"any.equal":            "Feld `{{label}}` Wert {{value}} ist nicht {{expected}}",
+	"any.valid":            "Feld `{{label}}` Wert {{value}} ist nicht in {{valids}}",
+	"any.decode":           "Feld `{{label}}` Wert {{value}} kann nicht in {{type}} dekodiert werden",
+	"string.base":          "Feld `{{label}}` Wert {{value}} ist kein String",
+	"string.check":         "Feld `{{label}}` Wert {{value}} {{error}}",
+	"string.equal":         "Feld `{{label}}` Wert {{value}} ist nicht {{expected}}",
+	"string.valid":         "Feld `{{label}}` Wert {{value}} ist nicht in {{valids}}",
+	"string.min":           "Feld `{{label}}` Wert {{value}} ist kürzer als {{limit}}",
+	"string.max":           "Feld `{{label}}` Wert {{value}} ist länger als {{limit}}",
+	"string.length":        "Feld `{{label}}` Wert {{value}} hat nicht die Länge {{limit}}",
+	"string.regex":         "Feld `{{label}}` Wert {{value}} entspricht nicht {{regex}}",
+	"string.alphanum":      "Feld `{{label}}` Wert {{value}} darf nur alphanumerische Zeichen enthalten",
+	"string.token":         "Feld `{{label}}` Wert {{value}} darf nur alphanumerische Zeichen und Unterstriche enthalten",
+	"string.email":         "Feld `{{label}}` Wert {{value}} ist keine gültige E-Mail-Adresse",
+	"string.uuid":          "Feld `{{label}}` Wert {{value}} ist keine gültige UUID",
+	"string.uri":           "Feld `{{label}}` Wert {{value}} ist keine gültige URI",
+	"string.hostname":      "Feld `{{label}}` Wert {{value}} ist kein gültiger Hostname",
+	"string.ip":            "Feld `{{label}}` Wert {{value}} ist keine gültige IP-Adresse",
+	"string.cidr":          "Feld `{{label}}` Wert {{value}} ist kein gültiger CIDR",
+	"string.hex":           "Feld `{{label}}` Wert {{value}} darf nur hexadezimale Zeichen enthalten",
+	"string.base64":        "Feld `{{label}}` Wert {{value}} ist kein gültiger Base64-String",
+	"string.country":       "Feld `{{label}}` Wert {{value}} ist kein gültiger Ländercode",
+	"number.base":          "Feld `{{label}}` Wert {{value}} ist keine Zahl",
+	"number.check":         "Feld `{{label}}` Wert {{value}} {{error}}",
+	"number.equal":         "Feld `{{label}}` Wert {{value}} ist nicht {{expected}}",
+	"number.valid":         "Feld `{{label}}` Wert {{value}} ist nicht in {{valids}}",
+	"number.min":           "Feld `{{label}}` Wert {{value}} ist kleiner als {{limit}}",
+	"number.max":           "Feld `{{label}}` Wert {{value}} ist größer als {{limit}}",
+	"number.greater":       "Feld `{{label}}` Wert {{value}} muss größer als {{limit}} sein",
+	"number.less":          "Feld `{{label}}` Wert {{value}} muss kleiner als {{limit}} sein",
+	"number.integer":       "Feld `{{label}}` Wert {{value}} ist keine ganze Zahl",
+	"number.parse":         "Feld `{{label}}` Wert {{value}} kann nicht in eine Zahl umgewandelt werden",
+	"bool.base":            "Feld `{{label}}` Wert {{value}} ist kein Boolean",
+	"bool.equal":           "Feld `{{label}}` Wert {{value}} ist nicht {{expected}}",
+	"bool.check":           "Feld `{{label}}` Wert {{value}} {{error}}",
+	"bool.valid":           "Feld `{{label}}` Wert {{value}} ist nicht in {{valids}}",
+	"object.base":          "Feld `{{label}}` Wert {{value}} ist kein Objekt",
+	"object.with":          "Feld `{{label}}` enthält {{peer}} nicht",
+	"object.without":       "Feld `{{label}}` darf {{keys}} nicht enthalten",
+	"object.withoutPeers":  "Feld `{{label}}` darf {{peers}} nicht zusammen mit {{key}} enthalten",
+	"object.unknown":       "Feld `{{label}}` ist nicht erlaubt",
+	"object.discriminator": "Feld `{{label}}` Wert {{value}} ist unbekannt, erwartet wird einer von {{valids}}",
+	"array.base":           "Feld `{{label}}` Wert {{value}} ist kein Array",
+	"array.check":          "Feld `{{label}}` Wert {{value}} {{error}}",
+	"array.min":            "Feld `{{label}}` Wert {{value}} hat weniger als {{limit}} Elemente",
+	"array.max":            "Feld `{{label}}` Wert {{value}} hat mehr als {{limit}} Elemente",
+	"array.length":         "Feld `{{label}}` Wert {{value}} hat nicht {{limit}} Elemente",
+	"alternatives.one":     "Feld `{{label}}` Wert {{value}} passt zu mehr als einem Schema",
+	"link.depth":           "Feld `{{label}}` ist tiefer als {{limit}} verschachtelt",
+	"date.base":            "Feld `{{label}}` Wert {{value}} ist kein gültiges Datum",
+	"date.check":           "Feld `{{label}}` Wert {{value}} {{error}}",
+	"date.min":             "Feld `{{label}}` Wert {{value}} ist früher als {{limit}}",
+	"date.max":             "Feld `{{label}}` Wert {{value}} ist später als {{limit}}",
+	"date.before":          "Feld `{{label}}` Wert {{value}} muss vor {{limit}} liegen",
+	"date.after":           "Feld `{{label}}` Wert {{value}} muss nach {{limit}} liegen",
+	"duration.base":        "Feld `{{label}}` Wert {{value}} ist keine gültige Dauer",
+	"duration.parse":       "Feld `{{label}}` Wert {{value}} kann nicht in eine Dauer umgewandelt werden",
+	"duration.check":       "Feld `{{label}}` Wert {{value}} {{error}}",
+	"duration.min":         "Feld `{{label}}` Wert {{value}} ist kürzer als {{limit}}",
+	"duration.max":         "Feld `{{label}}` Wert {{value}} ist länger als {{limit}}",
+	"byteSize.base":        "Feld `{{label}}` Wert {{value}} ist keine gültige Größe",
+	"byteSize.parse":       "Feld `{{label}}` Wert {{value}} kann nicht in eine Größe umgewandelt werden",
+	"byteSize.check":       "Feld `{{label}}` Wert {{value}} {{error}}",
+	"byteSize.min":         "Feld `{{label}}` Wert {{value}} ist kleiner als {{limit}}",
+	"byteSize.max":         "Feld `{{label}}` Wert {{value}} ist größer als {{limit}}",
+}
+
+var (
+	languagesMu sync.RWMutex
+	languages   = map[string]Messages{
+		"en": defaultMessages,
+		"zh": chineseMessages,
+		"de": germanMessages,
+	}
+)
+
+// RegisterMessages register the message catalog of the language, such as `fr` or `pt-BR`.
+// `en`, `zh` and `de` are built in, registering them again will replace the built-in catalog.
+// The rules missing in the catalog fallback to English.
+func RegisterMessages(lang string, messages Messages) {
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	languages[strings.ToLower(lang)] = messages
+}
+
+// lookupMessages find the catalog of the language, `zh-CN` fallback to `zh` if not registered.
+func lookupMessages(lang string) (Messages, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+	if messages, ok := languages[lang]; ok {
+		return messages, true
+	}
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		messages, ok := languages[lang[:i]]
+		return messages, ok
+	}
+	return nil, false
+}
+
+// UseMessages use the message catalog to render the errors of the validation.
+// The rules missing in the catalog fallback to English.
+func UseMessages(messages Messages) Option {
+	return func(o *options) {
+		o.messages = messages
+	}
+}
+
+// Language use the registered message catalog of the language to render the errors of the validation.
+// Unknown languages fallback to English.
+func Language(lang string) Option {
+	return func(o *options) {
+		o.messages, _ = lookupMessages(lang)
+	}
+}
+
+// AcceptLanguage make ValidateBody and ValidateQuery choose the message catalog
+// by the Accept-Language header of the request, among the registered languages.
+func AcceptLanguage() Option {
+	return func(o *options) {
+		o.acceptLanguage = true
+	}
+}
+
+// negotiateMessages choose the registered catalog with the highest quality in the Accept-Language header.
+func negotiateMessages(header string) (Messages, bool) {
+	type languageRange struct {
+		lang    string
+		quality float64
+	}
+	ranges := make([]languageRange, 0, 3)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := strings.TrimSpace(fields[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, languageRange{lang, quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	for _, r := range ranges {
+		if messages, ok := lookupMessages(r.lang); ok {
+			return messages, true
+		}
+	}
+	return nil, false
+}
+
+// message find the template of the rule, from the schema's messages, the catalog of the validation
+// and the default English catalog in order.
+func (ctx *Context) message(rule string) string {
+	for i := len(ctx.schemaMessages) - 1; i >= 0; i-- {
+		if template, ok := ctx.schemaMessages[i][rule]; ok {
+			return template
+		}
+	}
+	if template, ok := ctx.options.messages[rule]; ok {
+		return template
+	}
+	if template, ok := defaultMessages[rule]; ok {
+		return template
+	}
+	return "field `{{label}}` value {{value}} is invalid"
+}
+
+// pushMessages make the messages of the schema override the catalog until popMessages.
+func (ctx *Context) pushMessages(messages Messages) {
+	ctx.schemaMessages = append(ctx.schemaMessages, messages)
+}
+
+func (ctx *Context) popMessages() {
+	ctx.schemaMessages = ctx.schemaMessages[:len(ctx.schemaMessages)-1]
+}
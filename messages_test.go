@@ -0,0 +1,123 @@
+package jio
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUseMessages(t *testing.T) {
+	schema := Object().Keys(K{
+		"name": String().Min(3),
+	})
+	ctx := NewContext(map[string]interface{}{"name": "hi"}, UseMessages(Messages{
+		"string.min": "{{label}} needs {{limit}} characters",
+	}))
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "name needs 3 characters" {
+		t.Error("should use the messages")
+	}
+
+	ctx = NewContext(map[string]interface{}{"name": 1}, UseMessages(Messages{}))
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "field `name` value 1 is not string" {
+		t.Error("should fallback to english")
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	ctx := NewContext(nil, Language("zh-CN"))
+	String().Required().Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "字段 `` 不能为空" {
+		t.Error("should use chinese")
+	}
+
+	ctx = NewContext(nil, Language("de"))
+	String().Required().Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "Feld `` ist erforderlich" {
+		t.Error("should use german")
+	}
+
+	RegisterMessages("x-test", Messages{"any.required": "required!"})
+	ctx = NewContext(nil, Language("X-Test"))
+	String().Required().Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "required!" {
+		t.Error("should use registered messages")
+	}
+
+	ctx = NewContext(nil, Language("unknown"))
+	String().Required().Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "field `` is required" {
+		t.Error("should fallback to english")
+	}
+}
+
+func TestNegotiateMessages(t *testing.T) {
+	messages, ok := negotiateMessages("fr;q=0.9, de-DE;q=0.8, zh;q=0.5, *")
+	if !ok || messages["any.required"] != germanMessages["any.required"] {
+		t.Error("should choose german")
+	}
+	if _, ok := negotiateMessages("fr, zh;q=0"); ok {
+		t.Error("should not choose any")
+	}
+}
+
+func TestAnySchema_Messages(t *testing.T) {
+	schema := Object().Keys(K{
+		"name": String().Min(3).Messages(Messages{"string.min": "name too short"}),
+		"nick": String().Min(3),
+		"info": Object().Keys(K{
+			"title": String().Min(3),
+		}).Messages(Messages{"string.min": "{{label}} too short"}),
+	})
+
+	ctx := NewContext(map[string]interface{}{"name": "hi"})
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "name too short" {
+		t.Error("should override the message")
+	}
+
+	ctx = NewContext(map[string]interface{}{"nick": "hi"})
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "field `nick` value hi length less than 3" {
+		t.Error("should not affect other keys")
+	}
+
+	ctx = NewContext(map[string]interface{}{"info": map[string]interface{}{"title": "hi"}})
+	schema.Validate(ctx)
+	if ctx.Err == nil || ctx.Err.Error() != "info.title too short" {
+		t.Error("should apply to children")
+	}
+	if len(ctx.schemaMessages) != 0 {
+		t.Error("should pop the messages")
+	}
+}
+
+func TestValidateBody_AcceptLanguage(t *testing.T) {
+	schema := Object().Keys(K{
+		"name": String().Required(),
+	})
+	handler := ValidateBody(schema, DefaultErrorHandler, AcceptLanguage())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for lang, expected := range map[string]string{
+		"zh-CN,zh;q=0.9": "字段 `name` 不能为空",
+		"de":             "Feld `name` ist erforderlich",
+		"":               "field `name` is required",
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		var body map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body["message"] != expected {
+			t.Errorf("unexpected message %s", body["message"])
+		}
+	}
+}
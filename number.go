@@ -1,17 +1,16 @@
 package jio
 
 import (
-	"errors"
-	"fmt"
+	"encoding/json"
 	"math"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 // Number Generates a schema object that matches number data type
 func Number() *NumberSchema {
-	return &NumberSchema{
-		rules: make([]func(*Context), 0, 3),
-	}
+	return &NumberSchema{}
 }
 
 var _ Schema = new(NumberSchema)
@@ -19,191 +18,284 @@ var _ Schema = new(NumberSchema)
 // NumberSchema match number data type
 type NumberSchema struct {
 	baseSchema
+}
 
-	required *bool
-	rules    []func(*Context)
+func (n *NumberSchema) clone() *NumberSchema {
+	return &NumberSchema{baseSchema: n.baseSchema.clone()}
 }
 
 // SetPriority same as AnySchema.SetPriority
 func (n *NumberSchema) SetPriority(priority int) *NumberSchema {
-	n.priority = priority
-	return n
+	schema := n.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (n *NumberSchema) Messages(messages Messages) *NumberSchema {
+	schema := n.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (n *NumberSchema) Meta(key string, value interface{}) *NumberSchema {
+	schema := n.clone()
+	schema.setMeta(key, value)
+	return schema
 }
 
 // PrependTransform same as AnySchema.PrependTransform
 func (n *NumberSchema) PrependTransform(f func(*Context)) *NumberSchema {
-	n.rules = append([]func(*Context){f}, n.rules...)
-	return n
+	schema := n.clone()
+	schema.prependRule(f)
+	return schema
 }
 
 // Transform same as AnySchema.Transform
 func (n *NumberSchema) Transform(f func(*Context)) *NumberSchema {
-	n.rules = append(n.rules, f)
-	return n
+	schema := n.clone()
+	schema.appendRule(f)
+	return schema
 }
 
 // Required same as AnySchema.Required
 func (n *NumberSchema) Required() *NumberSchema {
-	n.required = boolPtr(true)
-	return n.PrependTransform(func(ctx *Context) {
+	schema := n.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Abort(fmt.Errorf("field `%s` is required", ctx.FieldPath()))
+			ctx.abortRule("any.required", nil)
 		}
 	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
 }
 
 // Optional same as AnySchema.Optional
 func (n *NumberSchema) Optional() *NumberSchema {
-	n.required = boolPtr(false)
-	return n.PrependTransform(func(ctx *Context) {
+	schema := n.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Skip()
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
 }
 
 // Default same as AnySchema.Default
 func (n *NumberSchema) Default(value float64) *NumberSchema {
-	n.required = boolPtr(false)
-	return n.PrependTransform(func(ctx *Context) {
+	var boxed interface{} = value
+	schema := n.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Value = value
+			ctx.Value = boxed
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Set same as AnySchema.Set
 func (n *NumberSchema) Set(value float64) *NumberSchema {
-	return n.Transform(func(ctx *Context) {
-		ctx.Value = value
+	var boxed interface{} = value
+	schema := n.Transform(func(ctx *Context) {
+		ctx.Value = boxed
 	})
+	schema.describeLast("set", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Equal same as AnySchema.Equal
 func (n *NumberSchema) Equal(value float64) *NumberSchema {
-	return n.Check(func(ctxValue float64) error {
-		if value != ctxValue {
-			return fmt.Errorf("is not %v", value)
-		}
-		return nil
+	return n.check("number.equal", map[string]interface{}{"expected": value}, func(ctxValue interface{}) bool {
+		return compareNumber(ctxValue, value) == 0
 	})
 }
 
 // When same as AnySchema.When
 func (n *NumberSchema) When(refPath string, condition interface{}, then Schema) *NumberSchema {
-	return n.Transform(func(ctx *Context) { n.when(ctx, refPath, condition, then) })
+	schema := n.Transform(func(ctx *Context) { n.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
 }
 
 // Check use the provided function to validate the value of the key.
-// Throws an error when the value is not float64.
+// Throws an error when the value is not number.
+// The json.Number, int64 and uint64 values are converted to float64 for the function, which may lose precision.
 func (n *NumberSchema) Check(f func(float64) error) *NumberSchema {
-	return n.Transform(func(ctx *Context) {
-		ctxValue, ok := ctx.Value.(float64)
+	schema := n.Transform(func(ctx *Context) {
+		ctxValue, ok := toFloat64(ctx.Value)
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not number", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("number.base", nil)
 			return
 		}
 		if err := f(ctxValue); err != nil {
-			ctx.Abort(fmt.Errorf("field `%s` value %v %s", ctx.FieldPath(), ctx.Value, err.Error()))
+			ctx.failCheck("number.check", err)
 		}
 	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// check is the built-in version of Check, throws an error of the rule when f returns false.
+// The value passed to f is float64, json.Number, int64 or uint64, so that it can be validated without rounding.
+func (n *NumberSchema) check(rule string, args map[string]interface{}, f func(interface{}) bool) *NumberSchema {
+	schema := n.Transform(func(ctx *Context) {
+		if !isNumber(ctx.Value) {
+			ctx.abortRule("number.base", nil)
+			return
+		}
+		if !f(ctx.Value) {
+			ctx.failRule(rule, args)
+		}
+	})
+	schema.describeLast(rule, args)
+	return schema
 }
 
 // Valid same as AnySchema.Valid
 func (n *NumberSchema) Valid(values ...float64) *NumberSchema {
-	return n.Check(func(ctxValue float64) error {
-		var isValid bool
+	return n.check("number.valid", map[string]interface{}{"valids": values}, func(ctxValue interface{}) bool {
 		for _, v := range values {
-			if v == ctxValue {
-				isValid = true
-				break
+			if compareNumber(ctxValue, v) == 0 {
+				return true
 			}
 		}
-		if !isValid {
-			return fmt.Errorf("not in %v", values)
-		}
-		return nil
+		return false
 	})
 }
 
 // Min check if the value is greater than or equal to the provided value.
 func (n *NumberSchema) Min(min float64) *NumberSchema {
-	return n.Check(func(ctxValue float64) error {
-		if ctxValue < min {
-			return fmt.Errorf("less than %v", min)
-		}
-		return nil
+	return n.check("number.min", map[string]interface{}{"limit": min}, func(ctxValue interface{}) bool {
+		return compareNumber(ctxValue, min) >= 0
 	})
 }
 
 // Max check if the value is less than or equal to the provided value.
 func (n *NumberSchema) Max(max float64) *NumberSchema {
-	return n.Check(func(ctxValue float64) error {
-		if ctxValue > max {
-			return fmt.Errorf("exceeded %v", max)
-		}
-		return nil
+	return n.check("number.max", map[string]interface{}{"limit": max}, func(ctxValue interface{}) bool {
+		return compareNumber(ctxValue, max) <= 0
+	})
+}
+
+// Greater check if the value is greater than the provided value.
+func (n *NumberSchema) Greater(limit float64) *NumberSchema {
+	return n.check("number.greater", map[string]interface{}{"limit": limit}, func(ctxValue interface{}) bool {
+		return compareNumber(ctxValue, limit) > 0
+	})
+}
+
+// Less check if the value is less than the provided value.
+func (n *NumberSchema) Less(limit float64) *NumberSchema {
+	return n.check("number.less", map[string]interface{}{"limit": limit}, func(ctxValue interface{}) bool {
+		return compareNumber(ctxValue, limit) < 0
 	})
 }
 
 // Integer check if the value is integer.
 func (n *NumberSchema) Integer() *NumberSchema {
-	return n.Check(func(ctxValue float64) error {
-		if ctxValue != math.Trunc(ctxValue) {
-			return errors.New("not integer")
-		}
-		return nil
-	})
+	return n.check("number.integer", nil, isInteger)
 }
 
 // Convert use the provided function to convert the value of the key.
-// Throws an error when the value is not float64.
+// Throws an error when the value is not number.
+// The json.Number, int64 and uint64 values are converted to float64 for the function, which may lose precision.
 func (n *NumberSchema) Convert(f func(float64) float64) *NumberSchema {
-	return n.Transform(func(ctx *Context) {
-		ctxValue, ok := ctx.Value.(float64)
+	schema := n.Transform(func(ctx *Context) {
+		ctxValue, ok := toFloat64(ctx.Value)
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not number", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("number.base", nil)
 			return
 		}
+		if value := f(ctxValue); value != ctxValue || !isFloat64(ctx.Value) {
+			ctx.Value = value
+		}
+	})
+	schema.describeLast("convert", nil)
+	return schema
+}
+
+// round is Convert for the rounding functions, the integer values are kept as is to avoid losing precision.
+func (n *NumberSchema) round(f func(float64) float64) *NumberSchema {
+	return n.Transform(func(ctx *Context) {
+		if !isNumber(ctx.Value) {
+			ctx.abortRule("number.base", nil)
+			return
+		}
+		if isInteger(ctx.Value) {
+			return
+		}
+		ctxValue, _ := toFloat64(ctx.Value)
 		ctx.Value = f(ctxValue)
 	})
 }
 
 // Ceil convert the value to the least integer value greater than or equal to the value.
 func (n *NumberSchema) Ceil() *NumberSchema {
-	return n.Convert(math.Ceil)
+	schema := n.round(math.Ceil)
+	schema.describeLast("ceil", nil)
+	return schema
 }
 
 // Floor convert the value to the greatest integer value less than or equal to the value.
 func (n *NumberSchema) Floor() *NumberSchema {
-	return n.Convert(math.Floor)
+	schema := n.round(math.Floor)
+	schema.describeLast("floor", nil)
+	return schema
 }
 
 // Round convert the value to the nearest integer, rounding half away from zero.
 func (n *NumberSchema) Round() *NumberSchema {
-	return n.Convert(math.Round)
+	schema := n.round(math.Round)
+	schema.describeLast("round", nil)
+	return schema
 }
 
-// ParseString convert the string value to float64.
+// ParseString convert the string value to float64, or json.Number with the UseNumber option.
 // Validation will be skipped when this value is not string.
 // But if this value is not a valid number, an error will be thrown.
 func (n *NumberSchema) ParseString() *NumberSchema {
-	return n.Transform(func(ctx *Context) {
+	schema := n.Transform(func(ctx *Context) {
 		if ctxValue, ok := ctx.Value.(string); ok {
+			if ctx.options.useNumber {
+				if !isJSONNumber(ctxValue) {
+					ctx.abortRule("number.parse", nil)
+					return
+				}
+				ctx.Value = json.Number(ctxValue)
+				return
+			}
 			value, err := strconv.ParseFloat(ctxValue, 64)
 			if err != nil {
-				ctx.Abort(fmt.Errorf("field `%s` value %v corvert to float64 failed", ctx.FieldPath(), ctx.Value))
+				ctx.abortRule("number.parse", nil)
 				return
 			}
 			ctx.Value = value
 		}
 	})
+	schema.describeLast("parse", nil)
+	return schema
+}
+
+// Describe same as AnySchema.Describe
+func (n *NumberSchema) Describe() *Description {
+	return n.describe("number")
 }
 
 // Validate same as AnySchema.Validate
+// The float64, json.Number, int64 and uint64 values are valid numbers, and int values are converted to float64.
 func (n *NumberSchema) Validate(ctx *Context) {
-	if n.required == nil {
-		n.Optional()
+	if n.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if n.messages != nil {
+		ctx.pushMessages(n.messages)
+		defer ctx.popMessages()
 	}
 	if ctxValue, ok := ctx.Value.(int); ok {
 		ctx.Value = float64(ctxValue)
@@ -214,9 +306,191 @@ func (n *NumberSchema) Validate(ctx *Context) {
 			return
 		}
 	}
-	if ctx.Err == nil {
-		if _, ok := (ctx.Value).(float64); !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not number", ctx.FieldPath(), ctx.Value))
+	if !isNumber(ctx.Value) {
+		ctx.abortRule("number.base", nil)
+	}
+}
+
+func isFloat64(value interface{}) bool {
+	_, ok := value.(float64)
+	return ok
+}
+
+// isNumber check if the value is float64, int64, uint64 or a valid json.Number.
+func isNumber(value interface{}) bool {
+	switch v := value.(type) {
+	case float64, int64, uint64:
+		return true
+	case json.Number:
+		return isJSONNumber(string(v))
+	}
+	return false
+}
+
+// toFloat64 convert the number to float64, which may lose precision.
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		if !isJSONNumber(string(v)) {
+			return 0, false
+		}
+		f, _ := strconv.ParseFloat(string(v), 64)
+		return f, true
+	}
+	return 0, false
+}
+
+// toRat convert the json.Number, int64 or uint64 to an exact rational number.
+func toRat(value interface{}) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case uint64:
+		return new(big.Rat).SetUint64(v), true
+	case json.Number:
+		if !isJSONNumber(string(v)) {
+			return nil, false
+		}
+		return new(big.Rat).SetString(string(v))
+	}
+	return nil, false
+}
+
+// compareNumber compare the number with the limit without rounding,
+// returns -1 if the value is less than the limit, 0 if equal, and +1 if greater.
+// The value must be a number.
+func compareNumber(value interface{}, limit float64) int {
+	// the rounding to float64 is monotonic, so the result is exact unless the rounded value equals the limit.
+	v, _ := toFloat64(value)
+	switch {
+	case v < limit:
+		return -1
+	case v > limit:
+		return 1
+	}
+	if _, ok := value.(float64); ok || math.IsInf(limit, 0) || math.IsNaN(limit) {
+		return 0
+	}
+	if limit == 0 {
+		return numberSign(value)
+	}
+	r, _ := toRat(value)
+	return r.Cmp(new(big.Rat).SetFloat64(limit))
+}
+
+// numberSign returns -1, 0 or +1 depending on the sign of the number.
+func numberSign(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return compareNumber(v, 0)
+	case int64:
+		return compareNumber(float64(v), 0)
+	case uint64:
+		if v == 0 {
+			return 0
+		}
+		return 1
+	case json.Number:
+		s := string(v)
+		if end := strings.IndexAny(s, "eE"); end >= 0 {
+			s = s[:end]
+		}
+		if strings.Trim(s, "-0.") == "" {
+			return 0
+		}
+		if s[0] == '-' {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// isInteger check if the number is integer without rounding.
+func isInteger(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		return v == math.Trunc(v)
+	case int64, uint64:
+		return true
+	case json.Number:
+		return isJSONNumber(string(v)) && isIntegerString(string(v))
+	}
+	return false
+}
+
+// isIntegerString check if the valid json number string is integer,
+// the digits are inspected directly since the exponent may be too large for big.Rat.
+func isIntegerString(s string) bool {
+	exp := 0
+	if end := strings.IndexAny(s, "eE"); end >= 0 {
+		e, err := strconv.Atoi(strings.TrimPrefix(s[end+1:], "+"))
+		if err != nil {
+			// the exponent overflows, the number is integer when the exponent is positive or the digits are zero.
+			return s[end+1] != '-' || numberSign(json.Number(s[:end])) == 0
 		}
+		exp, s = e, s[:end]
+	}
+	intPart, fracPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, fracPart = s[:dot], s[dot+1:]
+	}
+	digits := strings.TrimLeft(intPart, "-") + fracPart
+	trimmed := strings.TrimRight(digits, "0")
+	if strings.Trim(trimmed, "0") == "" {
+		return true
+	}
+	return int64(exp)-int64(len(fracPart))+int64(len(digits)-len(trimmed)) >= 0
+}
+
+// isJSONNumber check if the string is a valid json number.
+func isJSONNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	if s[0] == '-' {
+		s = s[1:]
+		if s == "" {
+			return false
+		}
+	}
+	switch {
+	case s[0] == '0':
+		s = s[1:]
+	case '1' <= s[0] && s[0] <= '9':
+		s = skipDigits(s[1:])
+	default:
+		return false
+	}
+	if len(s) >= 2 && s[0] == '.' && isDigit(s[1]) {
+		s = skipDigits(s[2:])
+	}
+	if len(s) >= 2 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s[0] == '+' || s[0] == '-' {
+			s = s[1:]
+		}
+		if s == "" || !isDigit(s[0]) {
+			return false
+		}
+		s = skipDigits(s)
+	}
+	return s == ""
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func skipDigits(s string) string {
+	for s != "" && isDigit(s[0]) {
+		s = s[1:]
 	}
+	return s
 }
@@ -1,7 +1,9 @@
 package jio
 
 import (
+	"encoding/json"
 	"errors"
+	"math"
 	"strconv"
 	"testing"
 )
@@ -292,3 +294,74 @@ func TestNumberSchema_ParseString(t *testing.T) {
 		t.Error("test parse string failed")
 	}
 }
+
+func TestNumberSchema_JSONNumber(t *testing.T) {
+	schema := Number().Integer().Max(9007199254740992)
+	ctx := NewContext(json.Number("9007199254740992"))
+	schema.Validate(ctx)
+	if ctx.Err != nil || ctx.Value != json.Number("9007199254740992") {
+		t.Error("should validate json.Number precisely")
+	}
+	ctx = NewContext(json.Number("9007199254740993"))
+	schema.Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should not round json.Number")
+	}
+	ctx = NewContext(json.Number("9007199254740993.5"))
+	Number().Integer().Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should not be integer")
+	}
+	ctx = NewContext(json.Number("1.5e1"))
+	Number().Integer().Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should be integer")
+	}
+	ctx = NewContext(json.Number("1e-1000000000"))
+	Number().Min(0).Integer().Validate(ctx)
+	if ctx.Err == nil || ctx.Err.(*ValidationError).Rule != "number.integer" {
+		t.Error("should compare huge exponents")
+	}
+	ctx = NewContext(json.Number("abc"))
+	Number().Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should reject invalid json.Number")
+	}
+}
+
+func TestNumberSchema_Int64(t *testing.T) {
+	ctx := NewContext(int64(math.MaxInt64))
+	Number().Min(math.MaxInt64).Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should compare int64 precisely")
+	}
+	ctx = NewContext(uint64(math.MaxUint64))
+	Number().Integer().Min(0).Round().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != uint64(math.MaxUint64) {
+		t.Error("should keep uint64")
+	}
+}
+
+func TestNumberSchema_ParseString_UseNumber(t *testing.T) {
+	ctx := NewContext("12345678901234567891", UseNumber())
+	Number().ParseString().Validate(ctx)
+	if ctx.Err != nil || ctx.Value != json.Number("12345678901234567891") {
+		t.Error("should parse to json.Number")
+	}
+	ctx = NewContext("0x10", UseNumber())
+	Number().ParseString().Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should reject invalid number")
+	}
+}
+
+func TestNumberSchema_GreaterAndLess(t *testing.T) {
+	schema := Number().Greater(0).Less(1)
+	for value, rule := range map[float64]string{0: "number.greater", 1: "number.less", 0.5: ""} {
+		ctx := NewContext(value)
+		schema.Validate(ctx)
+		if (rule == "" && ctx.Err != nil) || (rule != "" && (ctx.Err == nil || ctx.Err.(*ValidationError).Rule != rule)) {
+			t.Errorf("test greater and less failed with %v", value)
+		}
+	}
+}
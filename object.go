@@ -1,7 +1,6 @@
 package jio
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 )
@@ -14,22 +13,54 @@ type objectItem struct {
 // K object keys schema alias
 type K map[string]Schema
 
+// sort order the keys by priority from high to low, then by key name.
+// A key referencing another key with When will be validated after it, despite of the priority,
+// unless they reference each other.
 func (k K) sort() []objectItem {
 	objects := make([]objectItem, 0, len(k))
 	for key, schema := range k {
 		objects = append(objects, objectItem{key, schema})
 	}
 	sort.Slice(objects, func(i, j int) bool {
-		return objects[i].schema.Priority() > objects[j].schema.Priority()
+		if objects[i].schema.Priority() != objects[j].schema.Priority() {
+			return objects[i].schema.Priority() > objects[j].schema.Priority()
+		}
+		return objects[i].key < objects[j].key
 	})
-	return objects
+
+	sorted := make([]objectItem, 0, len(objects))
+	for len(objects) > 0 {
+		next := 0
+		for i, obj := range objects {
+			if !dependsOnAny(obj, objects) {
+				next = i
+				break
+			}
+		}
+		sorted = append(sorted, objects[next])
+		objects = append(objects[:next], objects[next+1:]...)
+	}
+	return sorted
+}
+
+// dependsOnAny check if the schema of the key references any of the other keys.
+// Since the path of the object is unknown, any segment of the reference path matching the key counts.
+func dependsOnAny(obj objectItem, objects []objectItem) bool {
+	for _, ref := range schemaRefs(obj.schema) {
+		for _, segment := range strings.Split(ref, ".") {
+			for _, other := range objects {
+				if other.key != obj.key && other.key == segment {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
 // Object Generates a schema object that matches object data type
 func Object() *ObjectSchema {
-	return &ObjectSchema{
-		rules: make([]func(*Context), 0, 3),
-	}
+	return &ObjectSchema{}
 }
 
 var _ Schema = new(ObjectSchema)
@@ -37,83 +68,109 @@ var _ Schema = new(ObjectSchema)
 // ObjectSchema match object data type
 type ObjectSchema struct {
 	baseSchema
+}
 
-	required *bool
-	rules    []func(*Context)
+func (o *ObjectSchema) clone() *ObjectSchema {
+	return &ObjectSchema{baseSchema: o.baseSchema.clone()}
 }
 
 // SetPriority same as AnySchema.SetPriority
 func (o *ObjectSchema) SetPriority(priority int) *ObjectSchema {
-	o.priority = priority
-	return o
+	schema := o.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (o *ObjectSchema) Messages(messages Messages) *ObjectSchema {
+	schema := o.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (o *ObjectSchema) Meta(key string, value interface{}) *ObjectSchema {
+	schema := o.clone()
+	schema.setMeta(key, value)
+	return schema
 }
 
 // PrependTransform same as AnySchema.PrependTransform
 func (o *ObjectSchema) PrependTransform(f func(*Context)) *ObjectSchema {
-	o.rules = append([]func(*Context){f}, o.rules...)
-	return o
+	schema := o.clone()
+	schema.prependRule(f)
+	return schema
 }
 
 // Transform same as AnySchema.Transform
 func (o *ObjectSchema) Transform(f func(*Context)) *ObjectSchema {
-	o.rules = append(o.rules, f)
-	return o
+	schema := o.clone()
+	schema.appendRule(f)
+	return schema
 }
 
 // Required same as AnySchema.Required
 func (o *ObjectSchema) Required() *ObjectSchema {
-	o.required = boolPtr(true)
-	return o.PrependTransform(func(ctx *Context) {
+	schema := o.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Abort(fmt.Errorf("field `%s` is required", ctx.FieldPath()))
+			ctx.abortRule("any.required", nil)
 		}
 	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
 }
 
 // Optional same as AnySchema.Optional
 func (o *ObjectSchema) Optional() *ObjectSchema {
-	o.required = boolPtr(false)
-	return o.PrependTransform(func(ctx *Context) {
+	schema := o.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Skip()
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
 }
 
 // Default same as AnySchema.Default
 func (o *ObjectSchema) Default(value map[string]interface{}) *ObjectSchema {
-	o.required = boolPtr(false)
-	return o.PrependTransform(func(ctx *Context) {
+	schema := o.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Value = value
+			ctx.Value = cloneValue(value)
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
 }
 
 // With require the presence of these keys.
 func (o *ObjectSchema) With(keys ...string) *ObjectSchema {
-	return o.Transform(func(ctx *Context) {
+	schema := o.Transform(func(ctx *Context) {
 		ctxValue, ok := ctx.Value.(map[string]interface{})
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not object", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("object.base", nil)
 			return
 		}
 		for _, key := range keys {
 			_, ok := ctxValue[key]
 			if !ok {
-				ctx.Abort(fmt.Errorf("field `%s` not contains %v", ctx.FieldPath(), key))
+				ctx.failRule("object.with", map[string]interface{}{"peer": key})
 				return
 			}
 		}
 	})
+	schema.describeLast("with", map[string]interface{}{"keys": keys})
+	return schema
 }
 
 // Without forbids the presence of these keys.
 func (o *ObjectSchema) Without(keys ...string) *ObjectSchema {
-	return o.Transform(func(ctx *Context) {
+	schema := o.Transform(func(ctx *Context) {
 		ctxValue, ok := ctx.Value.(map[string]interface{})
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not object", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("object.base", nil)
 			return
 		}
 		contains := make([]string, 0, 3)
@@ -124,40 +181,121 @@ func (o *ObjectSchema) Without(keys ...string) *ObjectSchema {
 			}
 		}
 		if len(contains) > 0 {
-			ctx.Abort(fmt.Errorf("field `%s` contains %v", ctx.FieldPath(), strings.Join(contains, ",")))
+			ctx.failRule("object.without", map[string]interface{}{"keys": strings.Join(contains, ",")})
+			return
+		}
+	})
+	schema.describeLast("without", map[string]interface{}{"keys": keys})
+	return schema
+}
+
+// WithoutPeers forbids the presence of the peer keys when the key is present.
+func (o *ObjectSchema) WithoutPeers(key string, peers ...string) *ObjectSchema {
+	schema := o.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(map[string]interface{})
+		if !ok {
+			ctx.abortRule("object.base", nil)
+			return
+		}
+		if _, ok := ctxValue[key]; !ok {
+			return
+		}
+		contains := make([]string, 0, 3)
+		for _, peer := range peers {
+			_, ok := ctxValue[peer]
+			if ok {
+				contains = append(contains, peer)
+			}
+		}
+		if len(contains) > 0 {
+			ctx.failRule("object.withoutPeers", map[string]interface{}{"key": key, "peers": strings.Join(contains, ",")})
+			return
+		}
+	})
+	schema.describeLast("withoutPeers", map[string]interface{}{"key": key, "peers": peers})
+	return schema
+}
+
+// additionalKeys validate the keys not in the known keys with the schema, or forbid them if the schema is nil.
+func (o *ObjectSchema) additionalKeys(known []string, schema Schema) *ObjectSchema {
+	knownKeys := make(map[string]bool, len(known))
+	for _, key := range known {
+		knownKeys[key] = true
+	}
+	result := o.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(map[string]interface{})
+		if !ok {
+			ctx.abortRule("object.base", nil)
 			return
 		}
+		var unknown []string
+		for key := range ctxValue {
+			if !knownKeys[key] {
+				unknown = append(unknown, key)
+			}
+		}
+		sort.Strings(unknown)
+		fields := ctx.fields
+
+		defer func() {
+			ctx.fields = fields
+			ctx.Value = ctxValue
+		}()
+
+		for _, key := range unknown {
+			ctx.skip = false
+			ctx.fields = append(fields, key)
+			ctx.Value = ctxValue[key]
+			if schema == nil {
+				ctx.failRule("object.unknown", nil)
+			} else {
+				schema.Validate(ctx)
+			}
+			if ctx.Err != nil && ctx.options.abortEarly {
+				return
+			}
+			if !ctx.skip {
+				ctxValue[key] = ctx.Value
+			}
+		}
+		ctx.skip = false
 	})
+	result.describeLast("additionalKeys", map[string]interface{}{"known": known, "schema": schema})
+	return result
 }
 
 // When same as AnySchema.When
 func (o *ObjectSchema) When(refPath string, condition interface{}, then Schema) *ObjectSchema {
-	return o.Transform(func(ctx *Context) { o.when(ctx, refPath, condition, then) })
+	schema := o.Transform(func(ctx *Context) { o.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
 }
 
 // Keys set the object keys's schema
 func (o *ObjectSchema) Keys(children K) *ObjectSchema {
-	return o.Transform(func(ctx *Context) {
+	objects := children.sort()
+	schema := o.Transform(func(ctx *Context) {
 		ctxValue, ok := ctx.Value.(map[string]interface{})
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not object", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("object.base", nil)
 			return
 		}
-		fields := make([]string, len(ctx.fields))
-		copy(fields, ctx.fields)
+		fields := ctx.fields
 
 		defer func() {
 			ctx.fields = fields
 			ctx.Value = ctxValue
 		}()
 
-		for _, obj := range children.sort() {
+		for _, obj := range objects {
 			value, _ := ctxValue[obj.key]
 			ctx.skip = false
 			ctx.fields = append(fields, obj.key)
 			ctx.Value = value
 			obj.schema.Validate(ctx)
-			if ctx.Err != nil {
+			if ctx.Err != nil && ctx.options.abortEarly {
 				return
 			}
 			if !ctx.skip {
@@ -166,12 +304,77 @@ func (o *ObjectSchema) Keys(children K) *ObjectSchema {
 		}
 		ctx.skip = false
 	})
+	for _, obj := range objects {
+		schema.addRefs(schemaRefs(obj.schema)...)
+	}
+	schema.describeLast("keys", map[string]interface{}{"keys": children})
+	return schema
+}
+
+// Discriminator selects the keys by the value of the key, such as the `type` of the polymorphic events.
+// The value of the key must be a string of the cases, otherwise an error of the `object.discriminator` rule is thrown
+// with the known values. Then the object is validated with the keys of the case, in addition to the keys set by Keys.
+func (o *ObjectSchema) Discriminator(key string, cases map[string]K) *ObjectSchema {
+	values := make([]string, 0, len(cases))
+	schemas := make(map[string]Schema, len(cases))
+	for value, children := range cases {
+		keys := make(K, len(children)+1)
+		for name, child := range children {
+			keys[name] = child
+		}
+		keys[key] = String().Required().Equal(value)
+		values = append(values, value)
+		schemas[value] = Object().Required().Keys(keys)
+	}
+	sort.Strings(values)
+
+	schema := o.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(map[string]interface{})
+		if !ok {
+			ctx.abortRule("object.base", nil)
+			return
+		}
+		value, _ := ctxValue[key]
+		name, _ := value.(string)
+		if caseSchema, ok := schemas[name]; ok {
+			caseSchema.Validate(ctx)
+			return
+		}
+
+		fields := ctx.fields
+		defer func() {
+			ctx.fields = fields
+			ctx.Value = ctxValue
+		}()
+		ctx.fields = append(fields, key)
+		ctx.Value = value
+		if value == nil {
+			ctx.abortRule("any.required", nil)
+			return
+		}
+		ctx.abortRule("object.discriminator", map[string]interface{}{"valids": values})
+	})
+	for _, value := range values {
+		schema.addRefs(schemaRefs(schemas[value])...)
+	}
+	schema.describeLast("discriminator", map[string]interface{}{"key": key, "cases": schemas})
+	return schema
+}
+
+// Describe same as AnySchema.Describe
+func (o *ObjectSchema) Describe() *Description {
+	return o.describe("object")
 }
 
 // Validate same as AnySchema.Validate
 func (o *ObjectSchema) Validate(ctx *Context) {
-	if o.required == nil {
-		o.Optional()
+	if o.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if o.messages != nil {
+		ctx.pushMessages(o.messages)
+		defer ctx.popMessages()
 	}
 	for _, rule := range o.rules {
 		rule(ctx)
@@ -179,9 +382,7 @@ func (o *ObjectSchema) Validate(ctx *Context) {
 			return
 		}
 	}
-	if ctx.Err == nil {
-		if _, ok := (ctx.Value).(map[string]interface{}); !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not object", ctx.FieldPath(), ctx.Value))
-		}
+	if _, ok := (ctx.Value).(map[string]interface{}); !ok {
+		ctx.abortRule("object.base", nil)
 	}
 }
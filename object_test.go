@@ -21,6 +21,55 @@ func TestK_sort(t *testing.T) {
 	}
 }
 
+func TestK_sortStable(t *testing.T) {
+	k := K{
+		"b": Any(),
+		"a": Any(),
+		"d": Any().SetPriority(1),
+		"c": Any(),
+	}
+	for i := 0; i < 10; i++ {
+		keys := make([]string, 0, 4)
+		for _, obj := range k.sort() {
+			keys = append(keys, obj.key)
+		}
+		if !reflect.DeepEqual(keys, []string{"d", "a", "b", "c"}) {
+			t.Error("should sort by priority then key")
+		}
+	}
+}
+
+func TestK_sortReferences(t *testing.T) {
+	k := K{
+		"a": String().When("z", "ip", String()),
+		"b": Object().Keys(K{
+			"c": String().When("y.value", "1", String()),
+		}),
+		"y": Object().SetPriority(-1),
+		"z": String().When("a", "1", String()),
+		"x": Any().SetPriority(1),
+	}
+	keys := make([]string, 0, 5)
+	for _, obj := range k.sort() {
+		keys = append(keys, obj.key)
+	}
+	if !reflect.DeepEqual(keys, []string{"x", "y", "b", "a", "z"}) {
+		t.Errorf("unexpected order %v", keys)
+	}
+
+	schema := Object().Keys(K{
+		"value": String().
+			When("type", "ip", String().Regex(`^\d+\.\d+\.\d+\.\d+$`)).
+			When("type", "domain", String().Regex(`^[a-z.]+$`)).Required(),
+		"type": String().Valid("ip", "domain").Default("ip"),
+	})
+	ctx := NewContext(map[string]interface{}{"value": "8.8.8.8"})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should validate type first")
+	}
+}
+
 func TestObjectSchema_SetPriority(t *testing.T) {
 	for _, priority := range []int{-1, 0, 100} {
 		if priority != Object().SetPriority(priority).Priority() {
@@ -120,11 +169,17 @@ func TestObjectSchema_Without(t *testing.T) {
 
 	ctx := NewContext(map[string]interface{}{"hi": "11", "faceair": "111"})
 	schema.Validate(ctx)
-	if ctx.Err == nil {
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "object.without" || err.Args["keys"] != "hi,faceair" {
 		t.Error("valid value test failed")
 	}
 
-	ctx = NewContext(map[string]interface{}{"hi": "11", "othor": "111"})
+	ctx = NewContext(map[string]interface{}{"faceair": "111"})
+	schema.Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should forbid every key")
+	}
+
+	ctx = NewContext(map[string]interface{}{"othor": "111"})
 	schema.Validate(ctx)
 	if ctx.Err != nil {
 		t.Error("invalid value test failed")
@@ -137,6 +192,22 @@ func TestObjectSchema_Without(t *testing.T) {
 	}
 }
 
+func TestObjectSchema_WithoutPeers(t *testing.T) {
+	schema := Object().WithoutPeers("hi", "faceair", "jio")
+
+	ctx := NewContext(map[string]interface{}{"hi": "11", "jio": "111"})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "object.withoutPeers" || err.Args["peers"] != "jio" {
+		t.Error("should forbid the peers with the key", ctx.Err)
+	}
+
+	ctx = NewContext(map[string]interface{}{"faceair": "11", "jio": "111"})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should allow the peers without the key")
+	}
+}
+
 func TestObjectSchema_When(t *testing.T) {
 	schema := Object().Keys(K{
 		"exist": Bool().Required(),
@@ -182,6 +253,58 @@ func TestObjectSchema_Keys(t *testing.T) {
 	}
 }
 
+func TestObjectSchema_Discriminator(t *testing.T) {
+	schema := Object().Keys(K{
+		"id": String().Required(),
+	}).Discriminator("type", map[string]K{
+		"click":  {"x": Number().Required(), "y": Number().Required()},
+		"scroll": {"offset": Number().Required().Min(0)},
+	})
+
+	ctx := NewContext(map[string]interface{}{"id": "1", "type": "click", "x": 1.0, "y": 2.0})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should validate the keys of the case")
+	}
+
+	ctx = NewContext(map[string]interface{}{"id": "1", "type": "scroll", "x": 1.0})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Path != "offset" || err.Rule != "any.required" {
+		t.Error("should select the keys by the discriminator")
+	}
+
+	ctx = NewContext(map[string]interface{}{"id": "1", "type": "drag"})
+	schema.Validate(ctx)
+	err, ok := ctx.Err.(*ValidationError)
+	if !ok || err.Path != "type" || err.Rule != "object.discriminator" ||
+		err.Message != "field `type` value drag is unknown, expected one of [click scroll]" {
+		t.Error("should reject the unknown discriminator", ctx.Err)
+	}
+
+	ctx = NewContext(map[string]interface{}{"id": "1"})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Path != "type" || err.Rule != "any.required" {
+		t.Error("should require the discriminator")
+	}
+
+	ctx = NewContext(map[string]interface{}{"type": "click", "x": 1.0, "y": 2.0})
+	schema.Validate(ctx)
+	if err, ok := ctx.Err.(*ValidationError); !ok || err.Path != "id" {
+		t.Error("should validate the other keys")
+	}
+
+	result := JSONSchema(schema)
+	oneOf, _ := result["oneOf"].([]interface{})
+	if len(oneOf) != 2 || !reflect.DeepEqual(result["discriminator"], map[string]interface{}{"propertyName": "type"}) ||
+		!reflect.DeepEqual(result["required"], []string{"id", "type"}) {
+		t.Error("should export the cases as oneOf with the discriminator", result)
+	}
+	click := oneOf[0].(map[string]interface{})["properties"].(map[string]interface{})["type"]
+	if click.(map[string]interface{})["const"] != "click" {
+		t.Error("should export the discriminator value as const")
+	}
+}
+
 func TestObjectSchema_Validate(t *testing.T) {
 	schema := Object()
 	ctx := NewContext(nil)
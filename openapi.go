@@ -0,0 +1,172 @@
+package jio
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OpenAPI records the schemas of the routes protected by the middlewares and renders an OpenAPI 3.1 document,
+// so that the API docs are generated from the same schemas that validate the requests.
+// It's safe for concurrent use.
+type OpenAPI struct {
+	title   string
+	version string
+
+	mu     sync.RWMutex
+	routes map[string]map[string]*openAPIOperation
+}
+
+type openAPIOperation struct {
+	query Schema
+	body  Schema
+}
+
+// openAPIRoute is the route that the middleware is registered to.
+type openAPIRoute struct {
+	api    *OpenAPI
+	method string
+	path   string
+}
+
+// NewOpenAPI Generates an OpenAPI registry with the title and version of the API.
+func NewOpenAPI(title, version string) *OpenAPI {
+	return &OpenAPI{
+		title:   title,
+		version: version,
+		routes:  make(map[string]map[string]*openAPIOperation),
+	}
+}
+
+// Route returns an option of ValidateBody, ValidateBodyInto and ValidateQuery,
+// which records the schema of the middleware as the request body or the query parameters of the route.
+func (api *OpenAPI) Route(method, path string) Option {
+	route := &openAPIRoute{api: api, method: strings.ToLower(method), path: path}
+	return func(o *options) {
+		o.route = route
+	}
+}
+
+// register record the schema of the route, in is "body" or "query".
+func (route *openAPIRoute) register(in string, schema Schema) {
+	if route == nil {
+		return
+	}
+	api := route.api
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	methods, ok := api.routes[route.path]
+	if !ok {
+		methods = make(map[string]*openAPIOperation)
+		api.routes[route.path] = methods
+	}
+	operation, ok := methods[route.method]
+	if !ok {
+		operation = &openAPIOperation{}
+		methods[route.method] = operation
+	}
+	if in == "body" {
+		operation.body = schema
+	} else {
+		operation.query = schema
+	}
+}
+
+// Document renders the OpenAPI 3.1 document, which can be encoded by encoding/json.
+// The body schemas are exported by JSONSchema as the json request bodies,
+// and the keys of the query schemas are exported as the query parameters.
+// The schemas of Link are exported in the components and referenced by their names.
+func (api *OpenAPI) Document() map[string]interface{} {
+	api.mu.RLock()
+	defer api.mu.RUnlock()
+	exporter := newJSONSchemaExporter("#/components/schemas/")
+	paths := make(map[string]interface{}, len(api.routes))
+	for path, methods := range api.routes {
+		item := make(map[string]interface{}, len(methods))
+		for method, operation := range methods {
+			item[method] = operation.document(exporter)
+		}
+		paths[path] = item
+	}
+	document := map[string]interface{}{
+		"openapi":           "3.1.0",
+		"jsonSchemaDialect": jsonSchemaDialect,
+		"info": map[string]interface{}{
+			"title":   api.title,
+			"version": api.version,
+		},
+		"paths": paths,
+	}
+	if len(exporter.defs) > 0 {
+		document["components"] = map[string]interface{}{"schemas": exporter.defs}
+	}
+	return document
+}
+
+// ServeHTTP respond the document in json.
+func (api *OpenAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := json.Marshal(api.Document())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(body)
+}
+
+func (operation *openAPIOperation) document(exporter *jsonSchemaExporter) map[string]interface{} {
+	result := map[string]interface{}{
+		"responses": map[string]interface{}{
+			"400": map[string]interface{}{"description": "The request is invalid"},
+		},
+	}
+	if operation.body != nil {
+		// the middlewares decode the body whatever the schema, so an empty body is always rejected.
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": exporter.export(operation.body)},
+			},
+		}
+	}
+	if operation.query != nil {
+		result["parameters"] = queryParameters(exporter, operation.query)
+	}
+	return result
+}
+
+// queryParameters export the keys of the object schema as the query parameters, sorted by name.
+func queryParameters(exporter *jsonSchemaExporter, schema Schema) []interface{} {
+	if typed, ok := schema.(interface{ Schema() Schema }); ok {
+		schema = typed.Schema()
+	}
+	object, ok := schema.(*ObjectSchema)
+	if !ok {
+		return []interface{}{}
+	}
+	keys := K{}
+	for _, desc := range object.descs {
+		if desc.name == "keys" {
+			for key, child := range desc.args["keys"].(K) {
+				keys[key] = child
+			}
+		}
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parameters := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     name,
+			"in":       "query",
+			"required": isRequired(keys[name]),
+			"schema":   exporter.export(keys[name]),
+		})
+	}
+	return parameters
+}
@@ -0,0 +1,82 @@
+package jio
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	api := NewOpenAPI("users", "1.0.0")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	ValidateBody(Object().Keys(K{
+		"name": String().Required(),
+	}), DefaultErrorHandler, api.Route("POST", "/users"))(handler)
+	ValidateQuery(Object().Keys(K{
+		"page": Number().ParseString().Min(1),
+		"q":    String().Required(),
+	}), DefaultErrorHandler, api.Route("GET", "/users"))(handler)
+
+	data, err := json.Marshal(api.Document())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"info":{"title":"users","version":"1.0.0"},"jsonSchemaDialect":"https://json-schema.org/draft/2020-12/schema","openapi":"3.1.0","paths":{"/users":{` +
+		`"get":{"parameters":[{"in":"query","name":"page","required":false,"schema":{"minimum":1,"type":["number","null"],"x-jio-rules":["parse"]}},{"in":"query","name":"q","required":true,"schema":{"type":"string"}}],"responses":{"400":{"description":"The request is invalid"}}},` +
+		`"post":{"requestBody":{"content":{"application/json":{"schema":{"properties":{"name":{"type":"string"}},"required":["name"],"type":["object","null"]}}},"required":true},"responses":{"400":{"description":"The request is invalid"}}}}}}`
+	if string(data) != expected {
+		t.Errorf("unexpected document %s", data)
+	}
+
+	w := httptest.NewRecorder()
+	api.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") || w.Body.String() != expected {
+		t.Error("should serve the document")
+	}
+}
+
+func TestOpenAPI_BodyInto(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+	api := NewOpenAPI("users", "1.0.0")
+	ValidateBodyInto(Object().Keys(K{"name": String()}), User{}, DefaultErrorHandler, api.Route("put", "/users/{id}"))
+	paths := api.Document()["paths"].(map[string]interface{})
+	if _, ok := paths["/users/{id}"].(map[string]interface{})["put"]; !ok {
+		t.Error("should record the route of ValidateBodyInto")
+	}
+}
+
+func TestOpenAPI_EmptyBody(t *testing.T) {
+	api := NewOpenAPI("users", "1.0.0")
+	handler := ValidateBody(Object(), DefaultErrorHandler, api.Route("POST", "/users"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	paths := api.Document()["paths"].(map[string]interface{})
+	body := paths["/users"].(map[string]interface{})["post"].(map[string]interface{})["requestBody"].(map[string]interface{})
+	if body["required"] != true {
+		t.Error("should require the body")
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(""))
+	r.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Error("should reject the empty body as documented", w.Code)
+	}
+}
+
+func TestOpenAPI_Link(t *testing.T) {
+	api := NewOpenAPI("comments", "1.0.0")
+	ValidateBody(linkTestSchema(), DefaultErrorHandler, api.Route("POST", "/comments"))
+	document := api.Document()
+	schemas := document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if _, ok := schemas["comment"]; !ok {
+		t.Error("should export the linked schemas in the components")
+	}
+	data, _ := json.Marshal(document["paths"])
+	if !strings.Contains(string(data), `"items":{"$ref":"#/components/schemas/comment"}`) {
+		t.Error("should reference the components", string(data))
+	}
+}
@@ -1,5 +1,10 @@
 package jio
 
+import (
+	"reflect"
+	"strings"
+)
+
 // Schema interface
 type Schema interface {
 	Priority() int
@@ -12,12 +17,93 @@ func boolPtr(value bool) *bool {
 
 type baseSchema struct {
 	priority int
+	messages Messages
+	required *bool
+	rules    []func(*Context)
+	descs    []ruleDesc
+	refs     []string
+	meta     map[string]interface{}
+}
+
+// ruleDesc describes a rule for the exporters, it's kept in the same order as the rules.
+// The rules added by Transform, Check and Convert are opaque closures and only have a name.
+type ruleDesc struct {
+	name string
+	args map[string]interface{}
+}
+
+// clone copy the schema with its own rules.
+// The builder methods always modify a clone, so a schema never changes once it's built
+// and can be shared between goroutines.
+func (b *baseSchema) clone() baseSchema {
+	schema := *b
+	schema.rules = append(make([]func(*Context), 0, len(b.rules)+1), b.rules...)
+	schema.descs = append(make([]ruleDesc, 0, len(b.descs)+1), b.descs...)
+	return schema
+}
+
+// setMeta set the metadata of the key, the map is copied since it's shared with the original schema.
+func (b *baseSchema) setMeta(key string, value interface{}) {
+	meta := make(map[string]interface{}, len(b.meta)+1)
+	for k, v := range b.meta {
+		meta[k] = v
+	}
+	meta[key] = value
+	b.meta = meta
+}
+
+// appendRule add the rule to the end of the rules.
+func (b *baseSchema) appendRule(f func(*Context)) {
+	b.rules = append(b.rules, f)
+	b.descs = append(b.descs, ruleDesc{name: "transform"})
+}
+
+// prependRule add the rule to the beginning of the rules.
+func (b *baseSchema) prependRule(f func(*Context)) {
+	b.rules = append([]func(*Context){f}, b.rules...)
+	b.descs = append([]ruleDesc{{name: "transform"}}, b.descs...)
+}
+
+// describeFirst name the first rule, which is added by PrependTransform.
+func (b *baseSchema) describeFirst(name string, args map[string]interface{}) {
+	b.descs[0] = ruleDesc{name: name, args: args}
+}
+
+// describeLast name the last rule, which is added by Transform.
+// The name of a rule code such as `string.min` is shortened to `min`.
+func (b *baseSchema) describeLast(name string, args map[string]interface{}) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	b.descs[len(b.descs)-1] = ruleDesc{name: name, args: args}
+}
+
+// base returns the embedded baseSchema, used to change the schema wrapped by Typed.
+func (b *baseSchema) base() *baseSchema {
+	return b
 }
 
 func (b *baseSchema) Priority() int {
 	return b.priority
 }
 
+// addRefs record the reference paths the rules depend on, used to order the keys of objects.
+func (b *baseSchema) addRefs(refs ...string) {
+	b.refs = append(b.refs[:len(b.refs):len(b.refs)], refs...)
+}
+
+func (b *baseSchema) references() []string {
+	return b.refs
+}
+
+// schemaRefs returns the reference paths the schema depends on.
+func schemaRefs(schema Schema) []string {
+	if s, ok := schema.(interface{ references() []string }); ok {
+		return s.references()
+	}
+	return nil
+}
+
 func (b *baseSchema) when(ctx *Context, refPath string, condition interface{}, then Schema) {
 	value, ok := ctx.Ref(refPath)
 	if !ok {
@@ -35,3 +121,29 @@ func (b *baseSchema) when(ctx *Context, refPath string, condition interface{}, t
 		then.Validate(ctx)
 	}
 }
+
+// cloneValue deep copy the objects and arrays of the value.
+// The default value is cloned every time it's used, so that transforms on it never modify the shared default.
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice && !rv.IsNil() {
+		clone := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(clone, rv)
+		return clone.Interface()
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package jio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchema_Immutable(t *testing.T) {
+	base := String().Min(3)
+	required := base.Required()
+	if len(base.rules) != 1 || len(required.rules) != 2 {
+		t.Error("should not modify the base schema")
+	}
+
+	ctx := NewContext(nil)
+	base.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("base schema should be optional")
+	}
+	ctx = NewContext(nil)
+	required.Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should be required")
+	}
+	if len(base.rules) != 1 {
+		t.Error("validate should not modify the schema")
+	}
+
+	withPriority := base.SetPriority(1)
+	if base.Priority() != 0 || withPriority.Priority() != 1 {
+		t.Error("set priority should not modify the base schema")
+	}
+}
+
+func TestSchema_DefaultNotShared(t *testing.T) {
+	schema := Object().Default(map[string]interface{}{
+		"list": []interface{}{" a "},
+	}).Keys(K{
+		"name": String().Default("faceair"),
+		"list": Array().Items(String().Trim()),
+	})
+	for i := 0; i < 2; i++ {
+		ctx := NewContext(nil)
+		schema.Validate(ctx)
+		if ctx.Err != nil {
+			t.Fatal(ctx.Err)
+		}
+		if !reflect.DeepEqual(ctx.Value, map[string]interface{}{"name": "faceair", "list": []interface{}{"a"}}) {
+			t.Error("should use the default value")
+		}
+		ctx.Value.(map[string]interface{})["name"] = "changed"
+	}
+}
+
+func TestCloneValue(t *testing.T) {
+	value := map[string]interface{}{
+		"object": map[string]interface{}{"1": 1},
+		"array":  []interface{}{map[string]interface{}{"2": 2}},
+		"ints":   []int{1, 2},
+	}
+	clone := cloneValue(value).(map[string]interface{})
+	if !reflect.DeepEqual(clone, value) {
+		t.Error("clone should be equal")
+	}
+	clone["object"].(map[string]interface{})["1"] = 0
+	clone["array"].([]interface{})[0].(map[string]interface{})["2"] = 0
+	clone["ints"].([]int)[0] = 0
+	if value["object"].(map[string]interface{})["1"] != 1 ||
+		value["array"].([]interface{})[0].(map[string]interface{})["2"] != 2 ||
+		value["ints"].([]int)[0] != 1 {
+		t.Error("should deep copy")
+	}
+}
@@ -1,16 +1,13 @@
 package jio
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 )
 
 // String Generates a schema object that matches string data type
 func String() *StringSchema {
-	return &StringSchema{
-		rules: make([]func(*Context), 0, 3),
-	}
+	return &StringSchema{}
 }
 
 var _ Schema = new(StringSchema)
@@ -18,197 +15,248 @@ var _ Schema = new(StringSchema)
 // StringSchema match string data type
 type StringSchema struct {
 	baseSchema
+}
 
-	required *bool
-	rules    []func(*Context)
+func (s *StringSchema) clone() *StringSchema {
+	return &StringSchema{baseSchema: s.baseSchema.clone()}
 }
 
 // SetPriority same as AnySchema.SetPriority
 func (s *StringSchema) SetPriority(priority int) *StringSchema {
-	s.priority = priority
-	return s
+	schema := s.clone()
+	schema.priority = priority
+	return schema
+}
+
+// Messages same as AnySchema.Messages
+func (s *StringSchema) Messages(messages Messages) *StringSchema {
+	schema := s.clone()
+	schema.messages = messages
+	return schema
+}
+
+// Meta same as AnySchema.Meta
+func (s *StringSchema) Meta(key string, value interface{}) *StringSchema {
+	schema := s.clone()
+	schema.setMeta(key, value)
+	return schema
 }
 
 // PrependTransform same as AnySchema.PrependTransform
 func (s *StringSchema) PrependTransform(f func(*Context)) *StringSchema {
-	s.rules = append([]func(*Context){f}, s.rules...)
-	return s
+	schema := s.clone()
+	schema.prependRule(f)
+	return schema
 }
 
 // Transform same as AnySchema.Transform
 func (s *StringSchema) Transform(f func(*Context)) *StringSchema {
-	s.rules = append(s.rules, f)
-	return s
+	schema := s.clone()
+	schema.appendRule(f)
+	return schema
 }
 
 // Required same as AnySchema.Required
 func (s *StringSchema) Required() *StringSchema {
-	s.required = boolPtr(true)
-	return s.PrependTransform(func(ctx *Context) {
+	schema := s.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Abort(fmt.Errorf("field `%s` is required", ctx.FieldPath()))
+			ctx.abortRule("any.required", nil)
 		}
 	})
+	schema.required = boolPtr(true)
+	schema.describeFirst("required", nil)
+	return schema
 }
 
 // Optional same as AnySchema.Optional
 func (s *StringSchema) Optional() *StringSchema {
-	s.required = boolPtr(false)
-	return s.PrependTransform(func(ctx *Context) {
+	schema := s.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
 			ctx.Skip()
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("optional", nil)
+	return schema
 }
 
 // Default same as AnySchema.Default
 func (s *StringSchema) Default(value string) *StringSchema {
-	s.required = boolPtr(false)
-	return s.PrependTransform(func(ctx *Context) {
+	var boxed interface{} = value
+	schema := s.PrependTransform(func(ctx *Context) {
 		if ctx.Value == nil {
-			ctx.Value = value
+			ctx.Value = boxed
 		}
 	})
+	schema.required = boolPtr(false)
+	schema.describeFirst("default", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Set same as AnySchema.Set
 func (s *StringSchema) Set(value string) *StringSchema {
-	return s.Transform(func(ctx *Context) {
-		ctx.Value = value
+	var boxed interface{} = value
+	schema := s.Transform(func(ctx *Context) {
+		ctx.Value = boxed
 	})
+	schema.describeLast("set", map[string]interface{}{"value": value})
+	return schema
 }
 
 // Equal same as AnySchema.Equal
 func (s *StringSchema) Equal(value string) *StringSchema {
-	return s.Check(func(ctxValue string) error {
-		if value != ctxValue {
-			return fmt.Errorf("is not %v", value)
-		}
-		return nil
+	return s.check("string.equal", map[string]interface{}{"expected": value}, func(ctxValue string) bool {
+		return value == ctxValue
 	})
 }
 
 // When same as AnySchema.When
 func (s *StringSchema) When(refPath string, condition interface{}, then Schema) *StringSchema {
-	return s.Transform(func(ctx *Context) { s.when(ctx, refPath, condition, then) })
+	schema := s.Transform(func(ctx *Context) { s.when(ctx, refPath, condition, then) })
+	schema.addRefs(refPath)
+	schema.addRefs(schemaRefs(then)...)
+	schema.describeLast("when", map[string]interface{}{"ref": refPath, "condition": condition, "then": then})
+	return schema
 }
 
 // Check use the provided function to validate the value of the key.
 // Throws an error when the value is not string.
 func (s *StringSchema) Check(f func(string) error) *StringSchema {
-	return s.Transform(func(ctx *Context) {
+	schema := s.Transform(func(ctx *Context) {
 		ctxValue, ok := ctx.Value.(string)
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not string", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("string.base", nil)
 			return
 		}
 		if err := f(ctxValue); err != nil {
-			ctx.Abort(fmt.Errorf("field `%s` value %v %s", ctx.FieldPath(), ctx.Value, err.Error()))
+			ctx.failCheck("string.check", err)
+		}
+	})
+	schema.describeLast("check", nil)
+	return schema
+}
+
+// check is the built-in version of Check, throws an error of the rule when f returns false.
+func (s *StringSchema) check(rule string, args map[string]interface{}, f func(string) bool) *StringSchema {
+	schema := s.Transform(func(ctx *Context) {
+		ctxValue, ok := ctx.Value.(string)
+		if !ok {
+			ctx.abortRule("string.base", nil)
+			return
+		}
+		if !f(ctxValue) {
+			ctx.failRule(rule, args)
 		}
 	})
+	schema.describeLast(rule, args)
+	return schema
 }
 
 // Valid same as AnySchema.Valid
 func (s *StringSchema) Valid(values ...string) *StringSchema {
-	return s.Check(func(ctxValue string) error {
-		var isValid bool
+	return s.check("string.valid", map[string]interface{}{"valids": values}, func(ctxValue string) bool {
 		for _, v := range values {
 			if v == ctxValue {
-				isValid = true
-				break
+				return true
 			}
 		}
-		if !isValid {
-			return fmt.Errorf("not in %v", values)
-		}
-		return nil
+		return false
 	})
 }
 
 // Min check if the length of this string is greater than or equal to the provided length.
 func (s *StringSchema) Min(min int) *StringSchema {
-	return s.Check(func(ctxValue string) error {
-		if len(ctxValue) < min {
-			return fmt.Errorf("length less than %d", min)
-		}
-		return nil
+	return s.check("string.min", map[string]interface{}{"limit": min}, func(ctxValue string) bool {
+		return len(ctxValue) >= min
 	})
 }
 
 // Max check if the length of this string is less than or equal to the provided length.
 func (s *StringSchema) Max(max int) *StringSchema {
-	return s.Check(func(ctxValue string) error {
-		if len(ctxValue) > max {
-			return fmt.Errorf("length exceeded %d", max)
-		}
-		return nil
+	return s.check("string.max", map[string]interface{}{"limit": max}, func(ctxValue string) bool {
+		return len(ctxValue) <= max
 	})
 }
 
 // Length check if the length of this string is equal to the provided length.
 func (s *StringSchema) Length(length int) *StringSchema {
-	return s.Check(func(ctxValue string) error {
-		if len(ctxValue) != length {
-			return fmt.Errorf("length not equal to %d", length)
-		}
-		return nil
+	return s.check("string.length", map[string]interface{}{"limit": length}, func(ctxValue string) bool {
+		return len(ctxValue) == length
 	})
 }
 
 // Regex check if the value is matched the regex.
 func (s *StringSchema) Regex(regex string) *StringSchema {
+	return s.regex("string.regex", regex)
+}
+
+func (s *StringSchema) regex(rule, regex string) *StringSchema {
 	re := regexp.MustCompile(regex)
-	return s.Check(func(ctxValue string) error {
-		if !re.MatchString(ctxValue) {
-			return fmt.Errorf("not match with %s", regex)
-		}
-		return nil
-	})
+	return s.check(rule, map[string]interface{}{"regex": regex}, re.MatchString)
 }
 
 // Alphanum check if the string value to only contain a-z, A-Z, and 0-9
 func (s *StringSchema) Alphanum() *StringSchema {
-	return s.Regex(`^[a-zA-Z0-9]+$`)
+	return s.regex("string.alphanum", `^[a-zA-Z0-9]+$`)
 }
 
 // Token check if the string value to only contain a-z, A-Z, 0-9, and underscore _
 func (s *StringSchema) Token() *StringSchema {
-	return s.Regex(`^\w+$`)
+	return s.regex("string.token", `^\w+$`)
 }
 
 // Convert use the provided function to convert the value of the key.
 // Throws an error when the value is not string.
 func (s *StringSchema) Convert(f func(string) string) *StringSchema {
-	return s.Transform(func(ctx *Context) {
+	schema := s.Transform(func(ctx *Context) {
 		ctxValue, ok := ctx.Value.(string)
 		if !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not string", ctx.FieldPath(), ctx.Value))
+			ctx.abortRule("string.base", nil)
 			return
 		}
-		ctx.Value = f(ctxValue)
+		if value := f(ctxValue); value != ctxValue {
+			ctx.Value = value
+		}
 	})
+	schema.describeLast("convert", nil)
+	return schema
 }
 
 // Lowercase convert the string value to lowercase.
 func (s *StringSchema) Lowercase() *StringSchema {
-	return s.Convert(strings.ToLower)
-
+	schema := s.Convert(strings.ToLower)
+	schema.describeLast("lowercase", nil)
+	return schema
 }
 
 // Uppercase convert the string value to uppercase.
 func (s *StringSchema) Uppercase() *StringSchema {
-	return s.Convert(strings.ToUpper)
+	schema := s.Convert(strings.ToUpper)
+	schema.describeLast("uppercase", nil)
+	return schema
 }
 
 // Trim  emoves whitespace from both sides of the string value.
 func (s *StringSchema) Trim() *StringSchema {
-	return s.Convert(strings.TrimSpace)
+	schema := s.Convert(strings.TrimSpace)
+	schema.describeLast("trim", nil)
+	return schema
+}
+
+// Describe same as AnySchema.Describe
+func (s *StringSchema) Describe() *Description {
+	return s.describe("string")
 }
 
 // Validate same as AnySchema.Validate
 func (s *StringSchema) Validate(ctx *Context) {
-	if s.required == nil {
-		s.Optional()
+	if s.required == nil && ctx.Value == nil {
+		ctx.Skip()
+		return
+	}
+	if s.messages != nil {
+		ctx.pushMessages(s.messages)
+		defer ctx.popMessages()
 	}
 	for _, rule := range s.rules {
 		rule(ctx)
@@ -216,9 +264,7 @@ func (s *StringSchema) Validate(ctx *Context) {
 			return
 		}
 	}
-	if ctx.Err == nil {
-		if _, ok := (ctx.Value).(string); !ok {
-			ctx.Abort(fmt.Errorf("field `%s` value %v is not string", ctx.FieldPath(), ctx.Value))
-		}
+	if _, ok := (ctx.Value).(string); !ok {
+		ctx.abortRule("string.base", nil)
 	}
 }
@@ -0,0 +1,381 @@
+package jio
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// FromStruct generates an ObjectSchema from the struct type, so that the constraints can be declared next to the fields.
+// The keys are named by the json tags, and the rules are read from the jio tags such as `jio:"required,min=3,max=18"`.
+//
+// The supported rules are:
+//
+//	required, optional        all types
+//	default=value             string, number and bool
+//	min=n, max=n, length=n    string length, number value and array length, length is not for number
+//	valid=a|b|c               string and number
+//	integer                   number
+//	alphanum, token, lowercase, uppercase, trim, regex=expr    string, regex must be the last rule
+//
+// The nested structs, slices and arrays generate nested schemas. The fields are required unless they're tagged
+// with optional or default, or their types can be nil, such as the pointers, the slices, the maps and the interfaces,
+// which are optional unless required. The integer fields are checked by Integer() and the unsigned fields by Min(0).
+// The fields of an embedded struct are promoted to the parent object, and the embedded struct itself can't have rules.
+// The types implementing json.Unmarshaler, maps and interfaces generate AnySchema.
+// A field with the `jio:"-"` or `json:"-"` tag is skipped.
+func FromStruct(typ reflect.Type) (*ObjectSchema, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jio: %s is not a struct", typ)
+	}
+	keys, err := structKeys(typ, "", map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return Object().Keys(keys), nil
+}
+
+// structRule is a rule parsed from the jio tag.
+type structRule struct {
+	name  string
+	param string
+}
+
+func parseStructTag(tag string) []structRule {
+	var rules []structRule
+	for tag != "" {
+		part := tag
+		if i := strings.IndexByte(tag, ','); i >= 0 && !strings.HasPrefix(tag, "regex=") {
+			part, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+		rule := structRule{name: part}
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			rule.name, rule.param = part[:i], part[i+1:]
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// structKeys generates the schemas of the struct fields.
+func structKeys(typ reflect.Type, path string, visiting map[reflect.Type]bool) (K, error) {
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	keys, embedded := K{}, K{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("jio")
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" || name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		// the fields of the embedded struct are promoted to the parent object, same as encoding/json.
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			if tag != "" {
+				return nil, fmt.Errorf("jio: field `%s`: the embedded struct can't have rules, tag its fields instead", field.Name)
+			}
+			if visiting[fieldType] {
+				continue
+			}
+			children, err := structKeys(fieldType, path, visiting)
+			if err != nil {
+				return nil, err
+			}
+			for key, child := range children {
+				embedded[key] = child
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		rules := parseStructTag(tag)
+		schema, err := fieldSchema(field.Type, fieldPath, rules, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if !nilable(field.Type) && !hasStructRule(rules, "required") && !hasStructRule(rules, "optional") && !hasStructRule(rules, "default") {
+			schema = requiredSchema(schema)
+		}
+		keys[name] = schema
+	}
+	for key, schema := range embedded {
+		if _, ok := keys[key]; !ok {
+			keys[key] = schema
+		}
+	}
+	return keys, nil
+}
+
+// nilable check if the value of the type can be nil, so that the field can be absent from the json.
+func nilable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+func fieldSchema(typ reflect.Type, path string, rules []structRule, visiting map[reflect.Type]bool) (Schema, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var schema Schema
+	var err error
+	switch kind := typ.Kind(); {
+	case reflect.PtrTo(typ).Implements(jsonUnmarshalerType):
+		schema, err = anyRules(Any(), rules)
+	case kind == reflect.String, (kind == reflect.Slice && typ.Elem().Kind() == reflect.Uint8):
+		schema, err = stringRules(String(), rules)
+	case kind == reflect.Bool:
+		schema, err = boolRules(Bool(), rules)
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		schema, err = numberRules(Number().Integer(), rules)
+	case kind >= reflect.Uint && kind <= reflect.Uintptr:
+		schema, err = numberRules(Number().Integer().Min(0), rules)
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		schema, err = numberRules(Number(), rules)
+	case kind == reflect.Slice || kind == reflect.Array:
+		items, itemsErr := fieldSchema(typ.Elem(), path, nil, visiting)
+		if itemsErr != nil {
+			return nil, itemsErr
+		}
+		schema, err = arrayRules(Array().Items(items), rules)
+	case kind == reflect.Struct && !visiting[typ]:
+		keys, keysErr := structKeys(typ, path, visiting)
+		if keysErr != nil {
+			return nil, keysErr
+		}
+		schema, err = objectRules(Object().Keys(keys), rules)
+	default:
+		// the recursive structs, maps and interfaces are not inspected.
+		schema, err = anyRules(Any(), rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jio: field `%s`: %v", path, err)
+	}
+	return schema, nil
+}
+
+func hasStructRule(rules []structRule, name string) bool {
+	for _, rule := range rules {
+		if rule.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func optionalSchema(schema Schema) Schema {
+	switch s := schema.(type) {
+	case *AnySchema:
+		return s.Optional()
+	case *StringSchema:
+		return s.Optional()
+	case *NumberSchema:
+		return s.Optional()
+	case *BoolSchema:
+		return s.Optional()
+	case *ArraySchema:
+		return s.Optional()
+	case *ObjectSchema:
+		return s.Optional()
+	case *AlternativesSchema:
+		return s.Optional()
+	case *DateSchema:
+		return s.Optional()
+	case *DurationSchema:
+		return s.Optional()
+	case *ByteSizeSchema:
+		return s.Optional()
+	}
+	return schema
+}
+
+func anyRules(schema *AnySchema, rules []structRule) (*AnySchema, error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			schema = schema.Required()
+		case "optional":
+			schema = schema.Optional()
+		default:
+			return nil, fmt.Errorf("unsupported rule %q", rule.name)
+		}
+	}
+	return schema, nil
+}
+
+func stringRules(schema *StringSchema, rules []structRule) (*StringSchema, error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			schema = schema.Required()
+		case "optional":
+			schema = schema.Optional()
+		case "default":
+			schema = schema.Default(rule.param)
+		case "min", "max", "length":
+			limit, err := strconv.Atoi(rule.param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", rule.name, rule.param)
+			}
+			switch rule.name {
+			case "min":
+				schema = schema.Min(limit)
+			case "max":
+				schema = schema.Max(limit)
+			default:
+				schema = schema.Length(limit)
+			}
+		case "valid":
+			schema = schema.Valid(strings.Split(rule.param, "|")...)
+		case "regex":
+			if _, err := regexp.Compile(rule.param); err != nil {
+				return nil, fmt.Errorf("invalid regex %q", rule.param)
+			}
+			schema = schema.Regex(rule.param)
+		case "alphanum":
+			schema = schema.Alphanum()
+		case "token":
+			schema = schema.Token()
+		case "lowercase":
+			schema = schema.Lowercase()
+		case "uppercase":
+			schema = schema.Uppercase()
+		case "trim":
+			schema = schema.Trim()
+		default:
+			return nil, fmt.Errorf("unsupported rule %q", rule.name)
+		}
+	}
+	return schema, nil
+}
+
+func numberRules(schema *NumberSchema, rules []structRule) (*NumberSchema, error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			schema = schema.Required()
+		case "optional":
+			schema = schema.Optional()
+		case "integer":
+			schema = schema.Integer()
+		case "default", "min", "max":
+			value, err := strconv.ParseFloat(rule.param, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", rule.name, rule.param)
+			}
+			switch rule.name {
+			case "default":
+				schema = schema.Default(value)
+			case "min":
+				schema = schema.Min(value)
+			default:
+				schema = schema.Max(value)
+			}
+		case "valid":
+			var values []float64
+			for _, param := range strings.Split(rule.param, "|") {
+				value, err := strconv.ParseFloat(param, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid valid %q", rule.param)
+				}
+				values = append(values, value)
+			}
+			schema = schema.Valid(values...)
+		default:
+			return nil, fmt.Errorf("unsupported rule %q", rule.name)
+		}
+	}
+	return schema, nil
+}
+
+func boolRules(schema *BoolSchema, rules []structRule) (*BoolSchema, error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			schema = schema.Required()
+		case "optional":
+			schema = schema.Optional()
+		case "default":
+			value, err := strconv.ParseBool(rule.param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default %q", rule.param)
+			}
+			schema = schema.Default(value)
+		default:
+			return nil, fmt.Errorf("unsupported rule %q", rule.name)
+		}
+	}
+	return schema, nil
+}
+
+func arrayRules(schema *ArraySchema, rules []structRule) (*ArraySchema, error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			schema = schema.Required()
+		case "optional":
+			schema = schema.Optional()
+		case "min", "max", "length":
+			limit, err := strconv.Atoi(rule.param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", rule.name, rule.param)
+			}
+			switch rule.name {
+			case "min":
+				schema = schema.Min(limit)
+			case "max":
+				schema = schema.Max(limit)
+			default:
+				schema = schema.Length(limit)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule %q", rule.name)
+		}
+	}
+	return schema, nil
+}
+
+func objectRules(schema *ObjectSchema, rules []structRule) (*ObjectSchema, error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			schema = schema.Required()
+		case "optional":
+			schema = schema.Optional()
+		default:
+			return nil, fmt.Errorf("unsupported rule %q", rule.name)
+		}
+	}
+	return schema, nil
+}
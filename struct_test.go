@@ -0,0 +1,167 @@
+package jio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type structTestBase struct {
+	ID int `json:"id" jio:"required,min=1"`
+}
+
+type structTestTag struct {
+	Name string `json:"name" jio:"required,lowercase,valid=go|js"`
+}
+
+type structTestUser struct {
+	structTestBase
+	Name     string          `json:"name" jio:"required,trim,min=3,max=18"`
+	Nickname *string         `json:"nickname" jio:"regex=^[a-z]{1,3}$"`
+	Age      uint8           `json:"age" jio:"default=18"`
+	Score    *float64        `json:"score" jio:"required,max=100"`
+	Admin    bool            `json:"admin" jio:"default=true"`
+	Tags     []structTestTag `json:"tags" jio:"max=2"`
+	Created  time.Time       `json:"created" jio:"optional"`
+	Parent   *structTestUser `json:"parent"`
+	Secret   string          `json:"-"`
+	Ignored  string          `jio:"-"`
+	private  string
+}
+
+func TestFromStruct(t *testing.T) {
+	schema, err := FromStruct(reflect.TypeOf(&structTestUser{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{
+		"id":    1.0,
+		"name":  " faceair ",
+		"score": 99.5,
+		"tags":  []interface{}{map[string]interface{}{"name": "GO"}},
+		"parent": map[string]interface{}{
+			"anything": true,
+		},
+		"Secret": 1,
+	}
+	ctx := NewContext(data)
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Fatal(ctx.Err)
+	}
+	if data["name"] != "faceair" || data["age"] != 18.0 || data["admin"] != true {
+		t.Error("should apply the transforms and defaults")
+	}
+	if data["tags"].([]interface{})[0].(map[string]interface{})["name"] != "go" {
+		t.Error("should validate the nested structs")
+	}
+
+	for path, value := range map[string]interface{}{
+		"id":           0.0,
+		"name":         "hi",
+		"nickname":     "abcd",
+		"age":          1.5,
+		"score":        nil,
+		"admin":        "yes",
+		"tags":         []interface{}{map[string]interface{}{}},
+		"tags.0.name":  "c",
+		"created.date": nil,
+	} {
+		data := map[string]interface{}{"id": 1.0, "name": "faceair", "score": 1.0}
+		switch path {
+		case "tags.0.name":
+			data["tags"] = []interface{}{map[string]interface{}{"name": value}}
+		case "created.date":
+			data["created"] = "2020-01-01"
+		default:
+			data[path] = value
+		}
+		ctx := NewContext(data)
+		schema.Validate(ctx)
+		if path == "created.date" {
+			if ctx.Err != nil {
+				t.Error("should accept any value of json.Unmarshaler")
+			}
+			continue
+		}
+		if ctx.Err == nil || !strings.HasPrefix(ctx.Err.(*ValidationError).Path, strings.Split(path, ".")[0]) {
+			t.Errorf("should throw error of %s", path)
+		}
+	}
+
+	ctx = NewContext(map[string]interface{}{"id": 1.0, "name": "faceair", "score": 1.0, "age": -1.0})
+	schema.Validate(ctx)
+	if ctx.Err == nil {
+		t.Error("should check unsigned fields")
+	}
+}
+
+func TestFromStruct_Presence(t *testing.T) {
+	schema, err := FromStruct(reflect.TypeOf(struct {
+		Count   int            `json:"count"`
+		Limit   *int           `json:"limit"`
+		Tags    []string       `json:"tags"`
+		Options map[string]int `json:"options"`
+		Page    int            `json:"page" jio:"optional"`
+		Size    int            `json:"size" jio:"default=10"`
+		Offset  *int           `json:"offset" jio:"required"`
+	}{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext(map[string]interface{}{"count": 1.0, "offset": 0.0})
+	schema.Validate(ctx)
+	if ctx.Err != nil {
+		t.Error("should make the nilable, optional and default fields optional", ctx.Err)
+	}
+	for _, key := range []string{"count", "offset"} {
+		data := map[string]interface{}{"count": 1.0, "offset": 0.0}
+		delete(data, key)
+		ctx := NewContext(data)
+		schema.Validate(ctx)
+		if err, ok := ctx.Err.(*ValidationError); !ok || err.Rule != "any.required" || err.Path != key {
+			t.Error("should require the field", key, ctx.Err)
+		}
+	}
+	required := JSONSchema(schema)["required"]
+	if !reflect.DeepEqual(required, []string{"count", "offset"}) {
+		t.Error("should export the required fields", required)
+	}
+}
+
+func TestFromStruct_Error(t *testing.T) {
+	if _, err := FromStruct(reflect.TypeOf("")); err == nil {
+		t.Error("should only accept struct")
+	}
+	if _, err := FromStruct(reflect.TypeOf(struct {
+		Name string `jio:"unknown"`
+	}{})); err == nil || err.Error() != "jio: field `Name`: unsupported rule \"unknown\"" {
+		t.Error("should throw unsupported rule")
+	}
+	if _, err := FromStruct(reflect.TypeOf(struct {
+		Items []struct {
+			Age int `json:"age" jio:"min=a"`
+		} `json:"items"`
+	}{})); err == nil || err.Error() != "jio: field `items.age`: invalid min \"a\"" {
+		t.Error("should throw invalid param")
+	}
+	if _, err := FromStruct(reflect.TypeOf(struct {
+		Name string `jio:"regex=["`
+	}{})); err == nil {
+		t.Error("should throw invalid regex")
+	}
+	if _, err := FromStruct(reflect.TypeOf(struct {
+		structTestBase `jio:"required"`
+	}{})); err == nil || err.Error() != "jio: field `structTestBase`: the embedded struct can't have rules, tag its fields instead" {
+		t.Error("should reject the rules of the embedded struct", err)
+	}
+}
+
+func TestParseStructTag(t *testing.T) {
+	rules := parseStructTag("required,min=3,regex=^a{1,2}$")
+	if !reflect.DeepEqual(rules, []structRule{{"required", ""}, {"min", "3"}, {"regex", "^a{1,2}$"}}) {
+		t.Error("test parse struct tag failed")
+	}
+}
@@ -0,0 +1,374 @@
+package jio
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Numeric is the constraint of the number types supported by TypedNumber.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// TypedString wraps the StringSchema with the typed API, see Typed.
+func TypedString(schema *StringSchema) *Typed[string] {
+	return &Typed[string]{schema: schema, kind: "string"}
+}
+
+// TypedBool wraps the BoolSchema with the typed API, see Typed.
+func TypedBool(schema *BoolSchema) *Typed[bool] {
+	return &Typed[bool]{schema: schema, kind: "bool"}
+}
+
+// TypedNumber wraps the NumberSchema with the typed API, see Typed.
+// The integer types are checked by Integer() and the unsigned types by Min(0),
+// a number out of the range of T throws an error of the `any.decode` rule.
+func TypedNumber[T Numeric](schema *NumberSchema) *Typed[T] {
+	switch reflect.TypeOf(T(0)).Kind() {
+	case reflect.Float32, reflect.Float64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema = schema.Integer().Min(0)
+	default:
+		schema = schema.Integer()
+	}
+	return &Typed[T]{schema: schema, kind: "number"}
+}
+
+var _ Schema = new(Typed[string])
+
+// Typed is a schema validating the values of the Go type T, built on the rule chain of the wrapped schema.
+// The functions of Check, Convert, Default and Valid take T, and ValidateValue returns T.
+// It can be used as a key of an object or an item of an array like any other schema.
+type Typed[T comparable] struct {
+	schema Schema
+	kind   string
+}
+
+// Schema returns the wrapped schema.
+func (t *Typed[T]) Schema() Schema {
+	return t.schema
+}
+
+// Priority same as AnySchema.Priority
+func (t *Typed[T]) Priority() int {
+	return t.schema.Priority()
+}
+
+func (t *Typed[T]) references() []string {
+	return schemaRefs(t.schema)
+}
+
+// with returns a typed schema wrapping the schema with the rule appended, or prepended if prepend is true.
+func (t *Typed[T]) with(prepend bool, f func(*Context)) *Typed[T] {
+	var schema Schema
+	switch s := t.schema.(type) {
+	case *StringSchema:
+		if prepend {
+			schema = s.PrependTransform(f)
+		} else {
+			schema = s.Transform(f)
+		}
+	case *NumberSchema:
+		if prepend {
+			schema = s.PrependTransform(f)
+		} else {
+			schema = s.Transform(f)
+		}
+	case *BoolSchema:
+		if prepend {
+			schema = s.PrependTransform(f)
+		} else {
+			schema = s.Transform(f)
+		}
+	}
+	return &Typed[T]{schema: schema, kind: t.kind}
+}
+
+// base returns the baseSchema of the wrapped schema.
+func (t *Typed[T]) base() *baseSchema {
+	return t.schema.(interface{ base() *baseSchema }).base()
+}
+
+// value convert the value of the context to T, throws an error of the base rule when it's not T.
+func (t *Typed[T]) value(ctx *Context) (T, bool) {
+	value, ok := typedValue[T](ctx.Value)
+	if !ok {
+		if ctx.Value != nil && isTypedKind(ctx.Value, t.kind) {
+			ctx.abortRule("any.decode", map[string]interface{}{"type": reflect.TypeOf(value).String()})
+		} else {
+			ctx.abortRule(t.kind+".base", nil)
+		}
+	}
+	return value, ok
+}
+
+// Required same as AnySchema.Required
+func (t *Typed[T]) Required() *Typed[T] {
+	schema := t.with(true, func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.abortRule("any.required", nil)
+		}
+	})
+	schema.base().required = boolPtr(true)
+	schema.base().describeFirst("required", nil)
+	return schema
+}
+
+// Optional same as AnySchema.Optional
+func (t *Typed[T]) Optional() *Typed[T] {
+	schema := t.with(true, func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Skip()
+		}
+	})
+	schema.base().required = boolPtr(false)
+	schema.base().describeFirst("optional", nil)
+	return schema
+}
+
+// Default same as AnySchema.Default
+func (t *Typed[T]) Default(value T) *Typed[T] {
+	boxed := untypedValue(value)
+	schema := t.with(true, func(ctx *Context) {
+		if ctx.Value == nil {
+			ctx.Value = boxed
+		}
+	})
+	schema.base().required = boolPtr(false)
+	schema.base().describeFirst("default", map[string]interface{}{"value": boxed})
+	return schema
+}
+
+// Valid same as AnySchema.Valid
+func (t *Typed[T]) Valid(values ...T) *Typed[T] {
+	schema := t.with(false, func(ctx *Context) {
+		ctxValue, ok := t.value(ctx)
+		if !ok {
+			return
+		}
+		for _, v := range values {
+			if v == ctxValue {
+				return
+			}
+		}
+		ctx.failRule(t.kind+".valid", map[string]interface{}{"valids": values})
+	})
+	schema.base().describeLast("valid", map[string]interface{}{"valids": values})
+	return schema
+}
+
+// Check use the provided function to validate the value of the key.
+// Throws an error when the value is not T.
+func (t *Typed[T]) Check(f func(T) error) *Typed[T] {
+	schema := t.with(false, func(ctx *Context) {
+		ctxValue, ok := t.value(ctx)
+		if !ok {
+			return
+		}
+		if err := f(ctxValue); err != nil {
+			ctx.failCheck(t.kind+".check", err)
+		}
+	})
+	schema.base().describeLast("check", nil)
+	return schema
+}
+
+// Convert use the provided function to convert the value of the key.
+// Throws an error when the value is not T.
+func (t *Typed[T]) Convert(f func(T) T) *Typed[T] {
+	schema := t.with(false, func(ctx *Context) {
+		ctxValue, ok := t.value(ctx)
+		if !ok {
+			return
+		}
+		if value := f(ctxValue); value != ctxValue {
+			ctx.Value = untypedValue(value)
+		}
+	})
+	schema.base().describeLast("convert", nil)
+	return schema
+}
+
+// Validate same as AnySchema.Validate
+// After the rules of the wrapped schema, the value must be convertible to T.
+func (t *Typed[T]) Validate(ctx *Context) {
+	t.schema.Validate(ctx)
+	if ctx.skip || ctx.Value == nil {
+		return
+	}
+	t.value(ctx)
+}
+
+// ValidateValue validate the value and returns it as T.
+// The zero value of T is returned if the value is absent and optional.
+func (t *Typed[T]) ValidateValue(value interface{}, opts ...Option) (T, error) {
+	ctx := NewContext(value, opts...)
+	t.Validate(ctx)
+	var zero T
+	if ctx.Err != nil {
+		return zero, ctx.Err
+	}
+	if result, ok := typedValue[T](ctx.Value); ok {
+		return result, nil
+	}
+	return zero, nil
+}
+
+// ValidateJSON same as ValidateJSONValue, and returns the validated value as T.
+func (t *Typed[T]) ValidateJSON(dataRaw *[]byte, opts ...Option) (T, error) {
+	var zero T
+	value, err := ValidateJSONValue(dataRaw, t, opts...)
+	if err != nil {
+		return zero, err
+	}
+	result, _ := typedValue[T](value)
+	return result, nil
+}
+
+// Describe same as AnySchema.Describe, returns the description of the wrapped schema.
+func (t *Typed[T]) Describe() *Description {
+	return Describe(t.schema)
+}
+
+// isTypedKind check if the value has the base type of the kind of schema.
+func isTypedKind(value interface{}, kind string) bool {
+	switch kind {
+	case "number":
+		return isNumber(value)
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	}
+	return false
+}
+
+// typedValue convert the validated value to T without losing precision,
+// returns false if the value is not of the kind of T or out of the range of T.
+func typedValue[T comparable](value interface{}) (T, bool) {
+	if v, ok := value.(T); ok {
+		return v, true
+	}
+	var result T
+	rv := reflect.ValueOf(&result).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		v, ok := value.(string)
+		if !ok {
+			return result, false
+		}
+		rv.SetString(v)
+	case reflect.Bool:
+		v, ok := value.(bool)
+		if !ok {
+			return result, false
+		}
+		rv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, ok := toInt64(value)
+		if !ok || rv.OverflowInt(v) {
+			return result, false
+		}
+		rv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, ok := toUint64(value)
+		if !ok || rv.OverflowUint(v) {
+			return result, false
+		}
+		rv.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, ok := toFloat64(value)
+		if !ok || rv.OverflowFloat(v) {
+			return result, false
+		}
+		rv.SetFloat(v)
+	default:
+		return result, false
+	}
+	return result, true
+}
+
+// untypedValue convert T to the value type of the schemas, the numbers are kept as int64 or uint64 to avoid losing precision.
+func untypedValue[T comparable](value T) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return value
+}
+
+// toInt64 convert the integer number to int64, returns false if it's not integer or out of range.
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return i, true
+		}
+		if r := jsonInteger(v); r != nil && r.IsInt64() {
+			return r.Int64(), true
+		}
+	}
+	return 0, false
+}
+
+// toUint64 convert the integer number to uint64, returns false if it's not integer or out of range.
+func toUint64(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < 0 || v >= math.MaxUint64 {
+			return 0, false
+		}
+		return uint64(v), true
+	case int64:
+		return uint64(v), v >= 0
+	case uint64:
+		return v, true
+	case json.Number:
+		if i, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return i, true
+		}
+		if r := jsonInteger(v); r != nil && r.IsUint64() {
+			return r.Uint64(), true
+		}
+	}
+	return 0, false
+}
+
+// jsonInteger returns the integer value of the json.Number such as 1e3, or nil if it's not integer.
+// The exponent is checked first since a large exponent makes big.Rat slow.
+func jsonInteger(v json.Number) *big.Int {
+	f, ok := toFloat64(v)
+	if !ok || math.Abs(f) > math.MaxUint64 || !isInteger(v) {
+		return nil
+	}
+	if f == 0 {
+		return new(big.Int)
+	}
+	r, _ := toRat(v)
+	return r.Num()
+}
@@ -0,0 +1,105 @@
+package jio
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTypedString(t *testing.T) {
+	schema := TypedString(String().Trim().Min(3)).
+		Convert(strings.ToUpper).
+		Check(func(value string) error {
+			if value == "ADMIN" {
+				return errors.New("is reserved")
+			}
+			return nil
+		}).
+		Default("guest")
+
+	value, err := schema.ValidateValue(" jio ")
+	if err != nil || value != "JIO" {
+		t.Error("should return the converted string")
+	}
+	value, err = schema.ValidateValue(nil)
+	if err != nil || value != "GUEST" {
+		t.Error("should use the default value")
+	}
+	_, err = schema.ValidateValue("admin")
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Rule != "string.check" {
+		t.Error("should check the typed value")
+	}
+	_, err = schema.ValidateValue(1.0)
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Rule != "string.base" {
+		t.Error("should throw base error")
+	}
+
+	_, err = TypedString(String()).Valid("a", "b").Required().ValidateValue(nil)
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Rule != "any.required" {
+		t.Error("should be required")
+	}
+	_, err = TypedString(String()).Valid("a", "b").ValidateValue("c")
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Rule != "string.valid" {
+		t.Error("should throw valid error")
+	}
+}
+
+func TestTypedNumber(t *testing.T) {
+	schema := TypedNumber[int64](Number().Min(1))
+	value, err := schema.ValidateValue(json.Number("9007199254740993"))
+	if err != nil || value != 9007199254740993 {
+		t.Error("should return the int64 without losing precision")
+	}
+	if _, err := schema.ValidateValue(1.5); err == nil || err.(*ValidationError).Rule != "number.integer" {
+		t.Error("should check integer")
+	}
+
+	uint8Schema := TypedNumber[uint8](Number()).Convert(func(value uint8) uint8 { return value * 2 })
+	if value, err := uint8Schema.ValidateValue(21.0); err != nil || value != 42 {
+		t.Error("should convert the uint8")
+	}
+	if _, err := uint8Schema.ValidateValue(256.0); err == nil || err.(*ValidationError).Rule != "any.decode" {
+		t.Error("should throw out of range")
+	}
+	if _, err := uint8Schema.ValidateValue(-1.0); err == nil || err.(*ValidationError).Rule != "number.min" {
+		t.Error("should check unsigned")
+	}
+
+	floatSchema := TypedNumber[float64](Number()).Valid(1.5, 2.5).Default(1.5)
+	if value, err := floatSchema.ValidateValue(nil); err != nil || value != 1.5 {
+		t.Error("should use the default value")
+	}
+	if _, err := floatSchema.ValidateValue(3.0); err == nil || err.(*ValidationError).Rule != "number.valid" {
+		t.Error("should throw valid error")
+	}
+}
+
+func TestTypedBool(t *testing.T) {
+	schema := TypedBool(Bool().Truthy("yes")).Required()
+	if value, err := schema.ValidateValue("yes"); err != nil || !value {
+		t.Error("should use the rules of BoolSchema")
+	}
+	if _, err := schema.Valid(false).ValidateValue(true); err == nil || err.(*ValidationError).Rule != "bool.valid" {
+		t.Error("should throw valid error")
+	}
+}
+
+func TestTyped_Keys(t *testing.T) {
+	type Level int
+	schema := Object().Keys(K{
+		"level": TypedNumber[Level](Number()).Default(3),
+		"name":  TypedString(String()).Required(),
+	})
+	data := []byte(`{"name": "jio"}`)
+	dataMap, err := ValidateJSON(&data, schema)
+	if err != nil || dataMap["level"] != int64(3) || string(data) != `{"level":3,"name":"jio"}` {
+		t.Error("should be used as object keys")
+	}
+
+	data = []byte(`5`)
+	level, err := TypedNumber[Level](Number()).ValidateJSON(&data)
+	if err != nil || level != 5 {
+		t.Error("should return the typed value")
+	}
+}
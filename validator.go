@@ -0,0 +1,77 @@
+package jio
+
+import "sync"
+
+// Validator is a compiled schema for hot paths, it reuses the contexts between validations.
+// It's safe for concurrent use.
+type Validator struct {
+	schema  Schema
+	options options
+	pool    sync.Pool
+}
+
+// Compile prepare the schema for repeated validations.
+// The options are applied to every validation of the validator.
+func Compile(schema Schema, opts ...Option) *Validator {
+	v := &Validator{
+		schema:  schema,
+		options: newOptions(opts),
+	}
+	v.pool.New = func() interface{} {
+		return &Context{fields: make([]string, 0, 8)}
+	}
+	return v
+}
+
+// Validate validate the value and returns the transformed value.
+// The options are applied after the options of Compile.
+func (v *Validator) Validate(value interface{}, opts ...Option) (interface{}, error) {
+	o := v.options
+	if len(opts) > 0 {
+		o = o.with(opts)
+	}
+	ctx := v.pool.Get().(*Context)
+	ctx.reset(value, o)
+	v.schema.Validate(ctx)
+	value, err := ctx.Value, ctx.Err
+	ctx.reset(nil, options{})
+	v.pool.Put(ctx)
+	return value, err
+}
+
+// ValidateJSON same as ValidateJSON, but use the compiled schema.
+func (v *Validator) ValidateJSON(dataRaw *[]byte, opts ...Option) (dataMap map[string]interface{}, err error) {
+	value, err := validateJSON(dataRaw, false, v.useNumber(opts), func(data interface{}) (interface{}, error) {
+		return v.Validate(data, opts...)
+	})
+	dataMap, _ = value.(map[string]interface{})
+	return dataMap, err
+}
+
+// ValidateJSONValue same as ValidateJSONValue, but use the compiled schema.
+func (v *Validator) ValidateJSONValue(dataRaw *[]byte, opts ...Option) (interface{}, error) {
+	return validateJSON(dataRaw, true, v.useNumber(opts), func(data interface{}) (interface{}, error) {
+		return v.Validate(data, opts...)
+	})
+}
+
+// ValidateJSONInto same as ValidateJSONInto, but use the compiled schema.
+func (v *Validator) ValidateJSONInto(dataRaw *[]byte, dst interface{}, opts ...Option) error {
+	value, err := v.ValidateJSONValue(dataRaw, opts...)
+	if err != nil {
+		return err
+	}
+	o := v.options
+	if len(opts) > 0 {
+		o = o.with(opts)
+	}
+	return decodeJSON(*dataRaw, value, dst, o)
+}
+
+// useNumber check if the json numbers should be decoded into json.Number.
+func (v *Validator) useNumber(opts []Option) bool {
+	if len(opts) > 0 {
+		return v.options.with(opts).useNumber
+	}
+	return v.options.useNumber
+}
@@ -0,0 +1,110 @@
+package jio
+
+import (
+	"testing"
+)
+
+var benchmarkSchema = Object().Keys(K{
+	"debug": Bool().Truthy("on").Required(),
+	"window": Object().Keys(K{
+		"title": String().Min(3).Max(18).Required(),
+		"size":  Array().Items(Number().Integer()).Length(2).Required(),
+		"state": String().Valid("normal", "maximized", "minimized").Default("normal"),
+	}).WithoutPeers("name", "title").Required(),
+	"tags": Array().Items(String().Trim().Lowercase()),
+})
+
+func benchmarkData() map[string]interface{} {
+	return map[string]interface{}{
+		"debug": "on",
+		"window": map[string]interface{}{
+			"title": "Sample Widget",
+			"size":  []interface{}{500.0, 500.0},
+		},
+		"tags": []interface{}{"go", "jio"},
+	}
+}
+
+func TestCompile(t *testing.T) {
+	validator := Compile(Object().Keys(K{
+		"name": String().Min(3).Required(),
+		"age":  Number().Integer(),
+	}), AbortEarly(false))
+
+	value, err := validator.Validate(map[string]interface{}{"name": "faceair", "age": 18.0})
+	if err != nil || value.(map[string]interface{})["name"] != "faceair" {
+		t.Error("should pass")
+	}
+
+	_, err = validator.Validate(map[string]interface{}{"name": "hi", "age": 1.5})
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 2 {
+		t.Error("should use the options of compile")
+	}
+
+	_, err = validator.Validate(map[string]interface{}{"name": "hi"}, Language("zh"))
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Error() != "字段 `name` 的值 hi 长度小于 3" {
+		t.Error("should use the options of validate")
+	}
+
+	data := []byte(`{"name": "faceair"}`)
+	if _, err := validator.ValidateJSON(&data); err != nil || string(data) != `{"name":"faceair"}` {
+		t.Error("should validate json")
+	}
+}
+
+func TestCompile_Allocs(t *testing.T) {
+	data := benchmarkData()
+	schemaAllocs := testing.AllocsPerRun(100, func() {
+		ctx := NewContext(data)
+		benchmarkSchema.Validate(ctx)
+		if ctx.Err != nil {
+			t.Fatal(ctx.Err)
+		}
+	})
+	validator := Compile(benchmarkSchema)
+	compiledAllocs := testing.AllocsPerRun(100, func() {
+		if _, err := validator.Validate(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if compiledAllocs >= schemaAllocs {
+		t.Errorf("compiled validator allocs %v should be less than %v", compiledAllocs, schemaAllocs)
+	}
+}
+
+func BenchmarkSchema_Validate(b *testing.B) {
+	data := benchmarkData()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ctx := NewContext(data)
+		benchmarkSchema.Validate(ctx)
+		if ctx.Err != nil {
+			b.Fatal(ctx.Err)
+		}
+	}
+}
+
+func BenchmarkValidator_Validate(b *testing.B) {
+	data := benchmarkData()
+	validator := Compile(benchmarkSchema)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := validator.Validate(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidator_ValidateParallel(b *testing.B) {
+	validator := Compile(benchmarkSchema)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		data := benchmarkData()
+		for pb.Next() {
+			if _, err := validator.Validate(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}